@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// DialectConfig describes the output format of a ping implementation with regular expressions,
+// each kind of line being tried against its alternatives in order. The values are read from
+// the named groups of the expression which matched, using the same names as the built-in dialects:
+//
+//   - header: host, resolvedIPAddress, payloadSize and payloadActualSize (all optional)
+//   - reply and error: replySize, fromHost, fromAddress, seqNo, ttl, time, timeOp, error and timeout
+//     (any non-empty value marks the reply as a timeout)
+//   - statsSeparator: IPAddress
+//   - statsLine1: packetsTransmitted, packetsReceived, duplicates, errors, packetLoss, time and warning
+//   - statsLine2: min, avg, max, mdev and unit, or pipeNo, ipg, ewma and ipgUnit on lines without
+//     a round-trip summary
+//
+// Without StatsLine2, the statistics end with stats line 1. Times printed without a unit are
+// in TimeUnit, e.g. "ms".
+type DialectConfig struct {
+	Name           string   `json:"name"`
+	Header         []string `json:"header"`
+	UnknownHost    []string `json:"unknownHost,omitempty"`
+	Reply          []string `json:"reply"`
+	Error          []string `json:"error,omitempty"`
+	StatsSeparator []string `json:"statsSeparator"`
+	StatsLine1     []string `json:"statsLine1"`
+	StatsLine2     []string `json:"statsLine2,omitempty"`
+	Ignored        []string `json:"ignored,omitempty"`
+	TimeUnit       string   `json:"timeUnit,omitempty"`
+}
+
+// NewDialect compiles the regular expressions of cfg into a dialect, which can then be registered with RegisterDialect.
+func NewDialect(cfg DialectConfig) (Dialect, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("dialect name is empty")
+	}
+
+	d := &rxDialect{name: cfg.Name, timeUnit: cfg.TimeUnit}
+	for _, f := range []struct {
+		context  string
+		patterns []string
+		rxs      *[]*regexp.Regexp
+		required bool
+	}{
+		{"header", cfg.Header, &d.header, true},
+		{"unknownHost", cfg.UnknownHost, &d.unknownHost, false},
+		{"reply", cfg.Reply, &d.reply, true},
+		// error lines are parsed as replies, after the actual ones
+		{"error", cfg.Error, &d.reply, false},
+		{"statsSeparator", cfg.StatsSeparator, &d.statsSeparator, true},
+		{"statsLine1", cfg.StatsLine1, &d.statsLine1, true},
+		{"statsLine2", cfg.StatsLine2, &d.statsLine2, false},
+		{"ignored", cfg.Ignored, &d.ignored, false},
+	} {
+		if f.required && len(f.patterns) == 0 {
+			return nil, fmt.Errorf("dialect %q: %s: no expression", cfg.Name, f.context)
+		}
+		for _, pattern := range f.patterns {
+			rx, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("dialect %q: %s: %w", cfg.Name, f.context, err)
+			}
+			*f.rxs = append(*f.rxs, rx)
+		}
+	}
+
+	return d, nil
+}
+
+// LoadDialects reads a JSON array of dialect configurations from r and compiles them with NewDialect.
+func LoadDialects(r io.Reader) ([]Dialect, error) {
+	var cfgs []DialectConfig
+	if err := json.NewDecoder(r).Decode(&cfgs); err != nil {
+		return nil, err
+	}
+
+	ds := make([]Dialect, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		d, err := NewDialect(cfg)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"regexp/syntax"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vendorConfig describes a router ping printing times without a unit.
+const vendorConfig = `[{
+	"name": "vendor",
+	"header": ["^PING (?P<host>[\\d.]+) size=(?P<payloadSize>\\d+) timeout=\\d+ms$"],
+	"reply": ["^reply from (?P<fromAddress>[\\d.]+) seq (?P<seqNo>\\d+) ttl (?P<ttl>\\d+) rtt (?P<time>[\\d.]+)$"],
+	"error": ["^(?P<timeout>no reply) for seq (?P<seqNo>\\d+)$", "^(?P<error>[a-z ]+) from (?P<fromAddress>[\\d.]+) seq (?P<seqNo>\\d+)$"],
+	"statsSeparator": ["^--- (?P<IPAddress>[\\d.]+) statistics ---$"],
+	"statsLine1": ["^(?P<packetsTransmitted>\\d+) sent, (?P<packetsReceived>\\d+) received, (?P<packetLoss>\\d+)% loss$"],
+	"statsLine2": ["^rtt min/avg/max/dev (?P<min>[\\d.]+)/(?P<avg>[\\d.]+)/(?P<max>[\\d.]+)/(?P<mdev>[\\d.]+)$"],
+	"timeUnit": "ms"
+}]`
+
+const vendorPayload = `PING 10.0.0.1 size=56 timeout=2000ms
+reply from 10.0.0.1 seq 1 ttl 64 rtt 1.25
+reply from 10.0.0.1 seq 2 ttl 64 rtt 1.31
+no reply for seq 3
+host unreachable from 10.0.0.254 seq 4
+
+--- 10.0.0.1 statistics ---
+4 sent, 2 received, 50% loss
+rtt min/avg/max/dev 1.25/1.28/1.31/0.03
+`
+
+func init() {
+	ds, err := LoadDialects(strings.NewReader(vendorConfig))
+	if err != nil {
+		panic(err)
+	}
+	for _, d := range ds {
+		RegisterDialect(d)
+	}
+}
+
+func TestLoadDialects(t *testing.T) {
+	po, err := Parse(vendorPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &PingOutput{
+		Dialect:           "vendor",
+		Host:              "10.0.0.1",
+		ResolvedIPAddress: "10.0.0.1",
+		PayloadSize:       56,
+		Replies: []PingReply{
+			{FromAddress: "10.0.0.1", SequenceNumber: 1, TTL: 64, Time: 1250 * time.Microsecond},
+			{FromAddress: "10.0.0.1", SequenceNumber: 2, TTL: 64, Time: 1310 * time.Microsecond},
+			{SequenceNumber: 3, Timeout: true},
+			{FromAddress: "10.0.0.254", SequenceNumber: 4, Error: "host unreachable", ErrorKind: ErrorUnknown},
+		},
+		Stats: PingStatistics{
+			IPAddress:          "10.0.0.1",
+			PacketsTransmitted: 4,
+			PacketsReceived:    2,
+			PacketLossPercent:  50,
+			PacketLoss:         50,
+			RoundTripMin:       1250 * time.Microsecond,
+			RoundTripAverage:   1280 * time.Microsecond,
+			RoundTripMax:       1310 * time.Microsecond,
+			RoundTripDeviation: 30 * time.Microsecond,
+		},
+	}
+	if !reflect.DeepEqual(po, expected) {
+		t.Errorf("expected %#v, but got %#v", expected, po)
+	}
+}
+
+func TestNewDialectInvalid(t *testing.T) {
+	for _, cfg := range []DialectConfig{
+		{},
+		{Name: "no-reply", Header: []string{"^PING"}, StatsSeparator: []string{"^---"}, StatsLine1: []string{"sent"}},
+		{Name: "bad-rx", Header: []string{"^PING ("}, Reply: []string{"reply"}, StatsSeparator: []string{"^---"}, StatsLine1: []string{"sent"}},
+	} {
+		if _, err := NewDialect(cfg); err == nil {
+			t.Errorf("expected an error for dialect %q", cfg.Name)
+		}
+	}
+
+	_, err := NewDialect(DialectConfig{Name: "bad-rx", Header: []string{"^PING ("}, Reply: []string{"reply"}, StatsSeparator: []string{"^---"}, StatsLine1: []string{"sent"}})
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("expected a *syntax.Error, but got %v", err)
+	}
+}
+
+func TestNewDialectWithoutGroups(t *testing.T) {
+	d, err := NewDialect(DialectConfig{
+		Name:           "no-groups",
+		Header:         []string{"^PING (?P<host>[\\d.]+) size=(?P<payloadSize>\\d+)$"},
+		Reply:          []string{"^reply seq (?P<seqNo>\\d+)$"},
+		StatsSeparator: []string{"^--- statistics ---$"},
+		StatsLine1:     []string{"^(?P<packetsTransmitted>\\d+) sent, (?P<packetsReceived>\\d+) received$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the separator has no group to read, it matches nonetheless
+	for _, lines := range [][]string{
+		{"PING 10.0.0.1 size=56", "--- statistics ---", "1 sent, 0 received"},
+		{"PING 10.0.0.1 size=56", "", "--- statistics ---", "1 sent, 0 received"},
+	} {
+		state := StateHeader
+		var po PingOutput
+		for _, line := range lines {
+			if state, err = d.ParseLine(state, line, &po); err != nil {
+				t.Fatalf("%q: %v", line, err)
+			}
+		}
+		if state != StateDone {
+			t.Errorf("expected state %v, but got %v", StateDone, state)
+		}
+	}
+}
+
+func TestNewDialectMinimal(t *testing.T) {
+	// neither a payload size nor stats line 2
+	d, err := NewDialect(DialectConfig{
+		Name:           "minimal",
+		Header:         []string{"^PING (?P<host>[\\d.]+)$"},
+		Reply:          []string{"^reply from (?P<fromAddress>[\\d.]+) seq (?P<seqNo>\\d+)$"},
+		StatsSeparator: []string{"^--- statistics ---$"},
+		StatsLine1:     []string{"^(?P<packetsTransmitted>\\d+) sent, (?P<packetsReceived>\\d+) received$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := StateHeader
+	var po PingOutput
+	for _, line := range []string{"PING 10.0.0.1", "reply from 10.0.0.1 seq 1", "--- statistics ---", "1 sent, 1 received"} {
+		if state, err = d.ParseLine(state, line, &po); err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+	}
+	if state != StateDone {
+		t.Errorf("expected state %v, but got %v", StateDone, state)
+	}
+	if po.Host != "10.0.0.1" || po.PayloadSize != 0 || len(po.Replies) != 1 {
+		t.Errorf("unexpected output %#v", po)
+	}
+}
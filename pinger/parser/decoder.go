@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+)
+
+// EventKind identifies which part of the ping output an Event carries.
+type EventKind int
+
+const (
+	// EventNone is returned for lines that do not complete any part of the output (e.g. separators).
+	EventNone EventKind = iota
+	// EventHeader is emitted once the header line has been parsed.
+	EventHeader
+	// EventReply is emitted for each reply or host error line.
+	EventReply
+	// EventStatistics is emitted once the statistics block is complete.
+	EventStatistics
+	// EventUnrecognized is emitted for lines skipped when parsing leniently.
+	EventUnrecognized
+	// EventSnapshot is emitted for each intermediate summary printed among the replies.
+	EventSnapshot
+)
+
+// Event is a part of the ping output, produced as soon as the lines making it up have been parsed.
+type Event struct {
+	Kind EventKind
+	// Header has only the header fields (Host, ResolvedIPAddress, PayloadSize and PayloadActualSize) set.
+	Header       *PingOutput
+	Reply        *PingReply
+	Stats        *PingStatistics
+	Unrecognized *UnrecognizedLine
+	Snapshot     *PingSnapshot
+}
+
+// maxLineSize is the longest line a Decoder accepts; the progress line printed by flood pings
+// only ends with the statistics, so it grows with the number of packets sent.
+const maxLineSize = 64 << 20
+
+// Decoder reads ping output from an input stream and returns its parts as they arrive.
+type Decoder struct {
+	scanner *bufio.Scanner
+	parser  LineParser
+}
+
+// NewDecoder returns a new decoder that reads ping output from r.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	return &Decoder{scanner: scanner, parser: LineParser{opts: newOptions(opts)}}
+}
+
+// Next blocks until the next event is available and returns it.
+// Once the input is exhausted it returns io.EOF, or an error wrapping ErrNotEnoughLines if the
+// statistics were never completed and partial output is not allowed.
+func (d *Decoder) Next() (Event, error) {
+	for d.scanner.Scan() {
+		ev, err := d.parser.Feed(d.scanner.Text())
+		if err != nil {
+			return Event{}, err
+		}
+		if ev.Kind != EventNone {
+			return ev, nil
+		}
+	}
+	if err := d.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	ev, err := d.parser.Close()
+	if err != nil {
+		return Event{}, err
+	}
+	if ev.Kind != EventNone {
+		return ev, nil
+	}
+
+	return Event{}, io.EOF
+}
+
+// Output returns everything decoded so far.
+func (d *Decoder) Output() *PingOutput {
+	return d.parser.Output()
+}
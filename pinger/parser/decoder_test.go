@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	for i := 0; i < len(payloads); i++ {
+		// capture range variables
+		payload := payloads[i]
+		t.Run(fmt.Sprintf("payload #%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := Parse(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				replies   []PingReply
+				snapshots []PingSnapshot
+				stats     *PingStatistics
+			)
+			dec := NewDecoder(strings.NewReader(payload))
+			ev, err := dec.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ev.Kind != EventHeader {
+				t.Fatalf("expected header event, but got %v", ev.Kind)
+			}
+			if ev.Header.Host != expected.Host || ev.Header.ResolvedIPAddress != expected.ResolvedIPAddress {
+				t.Errorf("expected header %q (%q), but got %q (%q)", expected.Host, expected.ResolvedIPAddress, ev.Header.Host, ev.Header.ResolvedIPAddress)
+			}
+			for {
+				ev, err := dec.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch ev.Kind {
+				case EventReply:
+					if stats != nil {
+						t.Error("reply event after statistics event")
+					}
+					replies = append(replies, *ev.Reply)
+				case EventSnapshot:
+					snapshots = append(snapshots, *ev.Snapshot)
+				case EventStatistics:
+					stats = ev.Stats
+				default:
+					t.Errorf("unexpected event %v", ev.Kind)
+				}
+			}
+
+			if !reflect.DeepEqual(replies, expected.Replies) {
+				t.Errorf("expected replies %#v, but got %#v", expected.Replies, replies)
+			}
+			if !reflect.DeepEqual(snapshots, expected.Snapshots) {
+				t.Errorf("expected snapshots %#v, but got %#v", expected.Snapshots, snapshots)
+			}
+			if stats == nil {
+				t.Fatal("no statistics event")
+			}
+			if *stats != expected.Stats {
+				t.Errorf("expected statistics %#v, but got %#v", expected.Stats, *stats)
+			}
+			if !reflect.DeepEqual(dec.Output(), expected) {
+				t.Errorf("expected output %#v, but got %#v", expected, dec.Output())
+			}
+		})
+	}
+}
+
+func TestDecoderStreaming(t *testing.T) {
+	r, w := io.Pipe()
+	defer r.Close()
+	dec := NewDecoder(r)
+
+	go io.WriteString(w, "PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.\n64 bytes from 127.0.0.1: icmp_seq=1 ttl=64 time=0.026 ms\n")
+
+	// both events must be available before the statistics are written
+	ev, err := dec.Next()
+	if err != nil || ev.Kind != EventHeader {
+		t.Fatalf("expected header event, but got %v (%v)", ev.Kind, err)
+	}
+	ev, err = dec.Next()
+	if err != nil || ev.Kind != EventReply {
+		t.Fatalf("expected reply event, but got %v (%v)", ev.Kind, err)
+	}
+	if ev.Reply.SequenceNumber != 1 {
+		t.Errorf("expected sequence number 1, but got %d", ev.Reply.SequenceNumber)
+	}
+
+	w.Close()
+	if _, err := dec.Next(); !errors.Is(err, ErrNotEnoughLines) {
+		t.Errorf("expected %v, but got %v", ErrNotEnoughLines, err)
+	}
+}
+
+func TestDecoderLongLine(t *testing.T) {
+	// the progress line of a flood ping is longer than the default buffer of bufio.Scanner
+	progress := strings.Repeat(".\b", 40000)
+	dec := NewDecoder(strings.NewReader("PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.\n" +
+		progress + "\n" +
+		"--- 10.0.0.1 ping statistics ---\n" +
+		"40000 packets transmitted, 40000 received, 0% packet loss, time 3101ms\n" +
+		"rtt min/avg/max/mdev = 0.010/0.012/0.090/0.004 ms, ipg/ewma 0.077/0.012 ms\n"))
+
+	var stats *PingStatistics
+	for {
+		ev, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Kind == EventStatistics {
+			stats = ev.Stats
+		}
+	}
+	if stats == nil || stats.PacketsReceived != 40000 {
+		t.Errorf("expected statistics for 40000 received packets, but got %+v", stats)
+	}
+}
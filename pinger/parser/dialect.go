@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Names of the built-in dialects.
+const (
+	DialectIPutils = "iputils"
+	DialectBSD     = "bsd"
+	DialectBusyBox = "busybox"
+	DialectWindows = "windows"
+)
+
+// Dialect parses the output format of one ping implementation.
+type Dialect interface {
+	// Name returns the name the dialect is registered with.
+	Name() string
+	// Detect reports whether line is the first line of output in this dialect; this
+	// includes failures printed instead of a header, such as unknown host messages.
+	Detect(line string) bool
+	// ParseLine parses a line of output expected to belong to state into po, and returns
+	// the state of the next line. ErrUnrecognizedLine and the other sentinel errors are
+	// returned for lines not belonging to state, without modifying po.
+	ParseLine(state State, line string, po *PingOutput) (State, error)
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   []Dialect
+)
+
+// RegisterDialect makes a dialect available for detection. Dialects are tried in the order
+// they were registered, after the built-in ones. It panics if a dialect with the same name
+// is already registered.
+func RegisterDialect(d Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	if d == nil {
+		panic("parser: RegisterDialect dialect is nil")
+	}
+	for _, registered := range dialects {
+		if registered.Name() == d.Name() {
+			panic(fmt.Sprintf("parser: RegisterDialect called twice for dialect %q", d.Name()))
+		}
+	}
+	dialects = append(dialects, d)
+}
+
+// LookupDialect returns the registered dialect with the given name, or nil.
+func LookupDialect(name string) Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	for _, d := range dialects {
+		if d.Name() == name {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// detectDialects returns the registered dialects detecting line as their first line, in order.
+func detectDialects(line string) []Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	var detected []Dialect
+	for _, d := range dialects {
+		if d.Detect(line) {
+			detected = append(detected, d)
+		}
+	}
+
+	return detected
+}
+
+// rxDialect is a dialect described by the alternatives tried, in order, for each kind of line.
+type rxDialect struct {
+	name           string
+	header         []*regexp.Regexp
+	unknownHost    []*regexp.Regexp
+	reply          []*regexp.Regexp
+	statsSeparator []*regexp.Regexp
+	statsLine1     []*regexp.Regexp
+	statsLine2     []*regexp.Regexp
+	// snapshot lines can appear among the replies
+	snapshot []*regexp.Regexp
+	// ignored lines can appear anywhere after the header
+	ignored []*regexp.Regexp
+	// timeUnit is used for the times printed without a unit
+	timeUnit string
+	// statsLine2Optional is set when stats line 2 can be printed without valid replies,
+	// as iputils does for the pipe size once ICMP errors have been received
+	statsLine2Optional bool
+}
+
+func (d *rxDialect) Name() string {
+	return d.name
+}
+
+func (d *rxDialect) Detect(line string) bool {
+	return matchFirst(d.header, line) != nil || matchesAny(d.unknownHost, line)
+}
+
+func (d *rxDialect) ParseLine(state State, line string, po *PingOutput) (State, error) {
+	if state != StateHeader && matchesAny(d.ignored, line) {
+		return state, nil
+	}
+
+	switch state {
+	case StateHeader:
+		if matchesAny(d.unknownHost, line) {
+			return state, ErrUnknownHost
+		}
+		if err := parseHeader(d.header, line, po); err != nil {
+			return state, err
+		}
+		return StateReplies, nil
+
+	case StateReplies:
+		if line == "" {
+			return StateStatsHeader, nil
+		}
+
+		// some ping outputs have a new line separator, others don't
+		if err := parseStatsHeader(d.statsSeparator, line, &po.Stats); err == nil {
+			return StateStatsLine1, nil
+		}
+
+		if matchesAny(d.snapshot, line) {
+			ps, err := parseSnapshot(d.snapshot, line)
+			if err != nil {
+				return state, err
+			}
+			ps.Replies = len(po.Replies)
+			po.Snapshots = append(po.Snapshots, ps)
+			return state, nil
+		}
+
+		pr, err := parseReply(d.reply, line, d.timeUnit)
+		if err != nil {
+			return state, err
+		}
+		po.Replies = append(po.Replies, pr)
+		return state, nil
+
+	case StateStatsHeader:
+		if err := parseStatsHeader(d.statsSeparator, line, &po.Stats); err != nil {
+			return state, err
+		}
+		return StateStatsLine1, nil
+
+	case StateStatsLine1:
+		if err := parseStatsLine1(d.statsLine1, line, d.timeUnit, &po.Stats); err != nil {
+			return state, err
+		}
+		// custom dialects may not print a second statistics line at all
+		if len(d.statsLine2) == 0 {
+			return StateDone, nil
+		}
+		// the pipe size only grows when errors acknowledge the packets in flight
+		if !expectsStatsLine2(po) && !(d.statsLine2Optional && po.Stats.Errors != 0) {
+			return StateDone, nil
+		}
+		return StateStatsLine2, nil
+
+	case StateStatsLine2:
+		if line == "" {
+			// iputils prints an empty line when it has nothing to summarize, e.g. without timing
+			return StateDone, nil
+		}
+		err := parseStatsLine2(d.statsLine2, line, d.timeUnit, &po.Stats)
+		if err == ErrMalformedStatsLine2 && !expectsStatsLine2(po) {
+			// the optional line is missing, this one follows the statistics
+			return StateDone, nil
+		}
+		if err != nil {
+			return state, err
+		}
+		return StateDone, nil
+	}
+
+	// anything following the statistics is ignored
+	return state, nil
+}
+
+// expectsStatsLine2 reports whether a round-trip summary line follows the packet counters,
+// which is only the case when at least one valid reply was received. Without any reply line,
+// as in quiet mode (ping -q), the packet counters tell.
+func expectsStatsLine2(po *PingOutput) bool {
+	if len(po.Replies) == 0 {
+		return po.Stats.PacketsReceived != 0
+	}
+	for _, pr := range po.Replies {
+		if pr.Error == `` && !pr.Timeout {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	RegisterDialect(&rxDialect{
+		name:           DialectIPutils,
+		header:         []*regexp.Regexp{headerRx},
+		unknownHost:    []*regexp.Regexp{unknownHostRx},
+		reply:          []*regexp.Regexp{lineRx, hostErrorLineRx3, hostErrorLineRx1, hostErrorLineRx2, noAnswerLineRx},
+		statsSeparator: []*regexp.Regexp{statsSeparatorRx},
+		statsLine1:     []*regexp.Regexp{statsLine1},
+		statsLine2:     []*regexp.Regexp{statsLine2, pipeNoLine},
+		snapshot:       []*regexp.Regexp{snapshotRx},
+		ignored:        []*regexp.Regexp{floodLineRx},
+
+		statsLine2Optional: true,
+	})
+	RegisterDialect(&rxDialect{
+		name:           DialectBSD,
+		header:         []*regexp.Regexp{headerRxAlt, headerRx6},
+		unknownHost:    []*regexp.Regexp{bsdUnknownHostRx},
+		reply:          []*regexp.Regexp{lineRx, hostErrorLineRx1, hostErrorLineRx2, bsdTimeoutLineRx},
+		statsSeparator: []*regexp.Regexp{statsSeparatorRx},
+		statsLine1:     []*regexp.Regexp{statsLine1},
+		statsLine2:     []*regexp.Regexp{statsLine2},
+	})
+	// BusyBox prints the same header as BSD, the first reply (or the round-trip summary) tells them apart
+	RegisterDialect(&rxDialect{
+		name:           DialectBusyBox,
+		header:         []*regexp.Regexp{headerRxAlt},
+		unknownHost:    []*regexp.Regexp{busyboxUnknownHostRx},
+		reply:          []*regexp.Regexp{busyboxLineRx},
+		statsSeparator: []*regexp.Regexp{statsSeparatorRx},
+		statsLine1:     []*regexp.Regexp{statsLine1},
+		statsLine2:     []*regexp.Regexp{busyboxStatsLine2},
+	})
+	RegisterDialect(&rxDialect{
+		name:           DialectWindows,
+		header:         []*regexp.Regexp{windowsHeaderRx},
+		unknownHost:    []*regexp.Regexp{windowsUnknownHostRx},
+		reply:          []*regexp.Regexp{windowsLineRx, windowsHostErrorLineRx1, windowsHostErrorLineRx2, windowsTimeoutLineRx},
+		statsSeparator: []*regexp.Regexp{windowsStatsSeparatorRx},
+		statsLine1:     []*regexp.Regexp{windowsStatsLine1},
+		statsLine2:     []*regexp.Regexp{windowsStatsLine2},
+		ignored:        []*regexp.Regexp{windowsStatsLine2Header},
+	})
+}
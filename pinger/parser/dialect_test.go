@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// probeDialect is iputils output with every line prefixed by the name of the probe running it.
+type probeDialect struct {
+	iputils Dialect
+}
+
+const probePrefix = "[probe-1] "
+
+func (d probeDialect) Name() string {
+	return "probe"
+}
+
+func (d probeDialect) Detect(line string) bool {
+	return strings.HasPrefix(line, probePrefix) && d.iputils.Detect(strings.TrimPrefix(line, probePrefix))
+}
+
+func (d probeDialect) ParseLine(state State, line string, po *PingOutput) (State, error) {
+	if !strings.HasPrefix(line, probePrefix) {
+		return state, ErrUnrecognizedLine
+	}
+
+	return d.iputils.ParseLine(state, strings.TrimPrefix(line, probePrefix), po)
+}
+
+func init() {
+	RegisterDialect(probeDialect{LookupDialect(DialectIPutils)})
+}
+
+func TestRegisterDialect(t *testing.T) {
+	var prefixed []string
+	for _, line := range strings.Split(strings.TrimSuffix(payloads[0], "\n"), "\n") {
+		prefixed = append(prefixed, probePrefix+line)
+	}
+
+	po, err := Parse(strings.Join(prefixed, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.Dialect != "probe" {
+		t.Errorf("expected dialect %q, but got %q", "probe", po.Dialect)
+	}
+	if len(po.Replies) != len(expectedTestCases[0].Replies) {
+		t.Errorf("expected %d replies, but got %d", len(expectedTestCases[0].Replies), len(po.Replies))
+	}
+	if po.Stats != expectedTestCases[0].Stats {
+		t.Errorf("expected statistics %#v, but got %#v", expectedTestCases[0].Stats, po.Stats)
+	}
+}
+
+func TestRegisterDialectTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+
+	RegisterDialect(probeDialect{})
+}
+
+func TestLookupDialect(t *testing.T) {
+	for _, name := range []string{DialectIPutils, DialectBSD, DialectBusyBox, DialectWindows} {
+		d := LookupDialect(name)
+		if d == nil {
+			t.Errorf("dialect %q not registered", name)
+			continue
+		}
+		if d.Name() != name {
+			t.Errorf("expected dialect %q, but got %q", name, d.Name())
+		}
+	}
+	if d := LookupDialect("unknown"); d != nil {
+		t.Errorf("expected no dialect, but got %q", d.Name())
+	}
+}
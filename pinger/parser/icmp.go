@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrorKind classifies the ICMP error reported by a reply.
+type ErrorKind int
+
+const (
+	// ErrorNone is used for replies which do not report an error.
+	ErrorNone ErrorKind = iota
+	// ErrorUnknown is used for errors whose wording is not recognized; their ICMP type and code are unknown.
+	ErrorUnknown
+	// ErrorOther is used for recognized ICMP errors which belong to none of the other kinds,
+	// such as "Source Route Failed" or "Frag reassembly time exceeded".
+	ErrorOther
+	ErrorNetUnreachable
+	ErrorHostUnreachable
+	ErrorProtocolUnreachable
+	ErrorPortUnreachable
+	ErrorAdminProhibited
+	ErrorTTLExceeded
+	ErrorFragmentationNeeded
+	ErrorRedirect
+	ErrorSourceQuench
+	ErrorParameterProblem
+)
+
+var errorKindNames = []string{
+	ErrorNone:                "none",
+	ErrorUnknown:             "unknown",
+	ErrorOther:               "other",
+	ErrorNetUnreachable:      "net unreachable",
+	ErrorHostUnreachable:     "host unreachable",
+	ErrorProtocolUnreachable: "protocol unreachable",
+	ErrorPortUnreachable:     "port unreachable",
+	ErrorAdminProhibited:     "administratively prohibited",
+	ErrorTTLExceeded:         "TTL exceeded",
+	ErrorFragmentationNeeded: "fragmentation needed",
+	ErrorRedirect:            "redirect",
+	ErrorSourceQuench:        "source quench",
+	ErrorParameterProblem:    "parameter problem",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+
+	return errorKindNames[k]
+}
+
+// RedirectType is the kind of route an ICMP redirect applies to.
+type RedirectType int
+
+const (
+	// RedirectNone is used for replies which are not redirects.
+	RedirectNone RedirectType = iota
+	RedirectNetwork
+	RedirectHost
+	RedirectTOSNetwork
+	RedirectTOSHost
+)
+
+var redirectTypeNames = []string{
+	RedirectNone:       "none",
+	RedirectNetwork:    "network",
+	RedirectHost:       "host",
+	RedirectTOSNetwork: "type of service and network",
+	RedirectTOSHost:    "type of service and host",
+}
+
+func (t RedirectType) String() string {
+	if t < 0 || int(t) >= len(redirectTypeNames) {
+		return fmt.Sprintf("RedirectType(%d)", int(t))
+	}
+
+	return redirectTypeNames[t]
+}
+
+// icmpError maps the wording of an error, as printed by iputils, BSD or Windows ping, to its ICMP type and code.
+type icmpError struct {
+	rx   *regexp.Regexp
+	kind ErrorKind
+	typ  uint8
+	code uint8
+}
+
+// icmpErrors are tried in order against the errors reported by IPv4 addresses.
+var icmpErrors = []icmpError{
+	{regexp.MustCompile(`(?i)^destination net(work)? unreachable for (type of service|tos)`), ErrorNetUnreachable, 3, 11},
+	{regexp.MustCompile(`(?i)^destination host unreachable for (type of service|tos)`), ErrorHostUnreachable, 3, 12},
+	{regexp.MustCompile(`(?i)^destination net(work)? unreachable`), ErrorNetUnreachable, 3, 0},
+	{regexp.MustCompile(`(?i)^destination host unreachable`), ErrorHostUnreachable, 3, 1},
+	{regexp.MustCompile(`(?i)^destination protocol unreachable`), ErrorProtocolUnreachable, 3, 2},
+	{regexp.MustCompile(`(?i)^destination port unreachable`), ErrorPortUnreachable, 3, 3},
+	{regexp.MustCompile(`(?i)^(frag needed and DF set|packet needs to be fragmented but DF set)`), ErrorFragmentationNeeded, 3, 4},
+	{regexp.MustCompile(`(?i)^source route failed`), ErrorOther, 3, 5},
+	{regexp.MustCompile(`(?i)^destination net(work)? unknown`), ErrorNetUnreachable, 3, 6},
+	{regexp.MustCompile(`(?i)^destination host unknown`), ErrorHostUnreachable, 3, 7},
+	{regexp.MustCompile(`(?i)^source host isolated`), ErrorOther, 3, 8},
+	{regexp.MustCompile(`(?i)^destination net prohibited`), ErrorAdminProhibited, 3, 9},
+	{regexp.MustCompile(`(?i)^destination host prohibited`), ErrorAdminProhibited, 3, 10},
+	{regexp.MustCompile(`(?i)^(packet filtered|communication (administratively )?prohibited|destination prohibited)`), ErrorAdminProhibited, 3, 13},
+	{regexp.MustCompile(`(?i)^(host )?precedence violation`), ErrorOther, 3, 14},
+	{regexp.MustCompile(`(?i)^precedence cutoff`), ErrorOther, 3, 15},
+	{regexp.MustCompile(`(?i)^source quench`), ErrorSourceQuench, 4, 0},
+	{regexp.MustCompile(`(?i)^redirect net(work)?\b`), ErrorRedirect, 5, 0},
+	{regexp.MustCompile(`(?i)^redirect host\b`), ErrorRedirect, 5, 1},
+	{regexp.MustCompile(`(?i)^redirect type of service and net(work)?\b`), ErrorRedirect, 5, 2},
+	{regexp.MustCompile(`(?i)^redirect type of service and host\b`), ErrorRedirect, 5, 3},
+	{regexp.MustCompile(`(?i)^(time to live exceeded|TTL expired in transit)`), ErrorTTLExceeded, 11, 0},
+	{regexp.MustCompile(`(?i)^(frag reassembly time exceeded|TTL expired during reassembly)`), ErrorOther, 11, 1},
+	{regexp.MustCompile(`(?i)^parameter problem`), ErrorParameterProblem, 12, 0},
+}
+
+// icmp6Errors are tried in order against the errors reported by IPv6 addresses, with ICMPv6 types and codes.
+var icmp6Errors = []icmpError{
+	{regexp.MustCompile(`(?i)^(destination unreachable: no route|destination net unreachable)`), ErrorNetUnreachable, 1, 0},
+	{regexp.MustCompile(`(?i)^(destination unreachable: administratively prohibited|destination prohibited|communication (administratively )?prohibited)`), ErrorAdminProhibited, 1, 1},
+	{regexp.MustCompile(`(?i)^destination unreachable: beyond scope`), ErrorOther, 1, 2},
+	{regexp.MustCompile(`(?i)^(destination unreachable: address unreachable|destination host unreachable)`), ErrorHostUnreachable, 1, 3},
+	{regexp.MustCompile(`(?i)^(destination unreachable: port unreachable|destination port unreachable)`), ErrorPortUnreachable, 1, 4},
+	{regexp.MustCompile(`(?i)^destination unreachable: source address failed`), ErrorAdminProhibited, 1, 5},
+	{regexp.MustCompile(`(?i)^destination unreachable: reject route`), ErrorAdminProhibited, 1, 6},
+	{regexp.MustCompile(`(?i)^(packet too big|packet needs to be fragmented)`), ErrorFragmentationNeeded, 2, 0},
+	{regexp.MustCompile(`(?i)^(time exceeded: hop limit|TTL expired in transit)`), ErrorTTLExceeded, 3, 0},
+	{regexp.MustCompile(`(?i)^(time exceeded: defragmentation failure|TTL expired during reassembly)`), ErrorOther, 3, 1},
+	{regexp.MustCompile(`(?i)^parameter problem: (erroneous header|wrong header field)`), ErrorParameterProblem, 4, 0},
+	{regexp.MustCompile(`(?i)^parameter problem: unknown (next)?header`), ErrorParameterProblem, 4, 1},
+	{regexp.MustCompile(`(?i)^parameter problem: (unknown|unrecognized) option`), ErrorParameterProblem, 4, 2},
+	{regexp.MustCompile(`(?i)^parameter problem`), ErrorParameterProblem, 4, 0},
+	{regexp.MustCompile(`(?i)^redirect`), ErrorRedirect, 137, 0},
+}
+
+// mtuRx matches the next-hop MTU advertised by fragmentation needed errors, e.g. "(mtu = 1400)"
+// for iputils, "(MTU 1400)" for BSD and "mtu=1280" for IPv6.
+var mtuRx = regexp.MustCompile(`(?i)\bmtu(?: = | |=)(?P<mtu>\d+)`)
+
+// nextHopRx matches the gateway advertised by redirects, e.g. "(New nexthop: 192.168.1.254)"
+// for iputils and "(New addr: 192.168.1.254)" for BSD.
+var nextHopRx = regexp.MustCompile(`\(New (?:nexthop|addr): (?P<nextHop>` + addressPattern + `)\)`)
+
+// classifyError returns the kind, ICMP type and code of the error reported by fromAddress;
+// errors reported without an address are assumed to be ICMPv4 ones.
+func classifyError(fromAddress, errorText string) (ErrorKind, uint8, uint8) {
+	table := icmpErrors
+	if strings.Contains(fromAddress, ":") {
+		table = icmp6Errors
+	}
+	for _, e := range table {
+		if e.rx.MatchString(errorText) {
+			return e.kind, e.typ, e.code
+		}
+	}
+
+	return ErrorUnknown, 0, 0
+}
+
+// redirectType returns the type of a redirect from its ICMP type and code.
+func redirectType(icmpType, icmpCode uint8) RedirectType {
+	// ICMPv6 redirects are only sent for hosts
+	if icmpType == 137 {
+		return RedirectHost
+	}
+
+	return RedirectNetwork + RedirectType(icmpCode)
+}
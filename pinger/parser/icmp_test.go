@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tc := range []struct {
+		fromAddress string
+		error       string
+		kind        ErrorKind
+		typ, code   uint8
+	}{
+		// iputils
+		{"10.0.0.1", "Destination Net Unreachable", ErrorNetUnreachable, 3, 0},
+		{"10.0.0.1", "Destination Host Unreachable", ErrorHostUnreachable, 3, 1},
+		{"10.0.0.1", "Destination Protocol Unreachable", ErrorProtocolUnreachable, 3, 2},
+		{"10.0.0.1", "Destination Port Unreachable", ErrorPortUnreachable, 3, 3},
+		{"10.0.0.1", "Frag needed and DF set (mtu = 1400)", ErrorFragmentationNeeded, 3, 4},
+		{"10.0.0.1", "Source Route Failed", ErrorOther, 3, 5},
+		{"10.0.0.1", "Destination Host Prohibited", ErrorAdminProhibited, 3, 10},
+		{"10.0.0.1", "Destination Host Unreachable for Type of Service", ErrorHostUnreachable, 3, 12},
+		{"10.0.0.1", "Packet filtered", ErrorAdminProhibited, 3, 13},
+		{"10.0.0.1", "Source Quench", ErrorSourceQuench, 4, 0},
+		{"10.0.0.1", "Redirect Network(New nexthop: 10.0.0.254)", ErrorRedirect, 5, 0},
+		{"10.0.0.1", "Redirect Host(New nexthop: 10.0.0.254)", ErrorRedirect, 5, 1},
+		{"10.0.0.1", "Redirect Type of Service and Host(New nexthop: 10.0.0.254)", ErrorRedirect, 5, 3},
+		{"10.0.0.1", "Time to live exceeded", ErrorTTLExceeded, 11, 0},
+		{"10.0.0.1", "Frag reassembly time exceeded", ErrorOther, 11, 1},
+		{"10.0.0.1", "Parameter problem: pointer = 20", ErrorParameterProblem, 12, 0},
+		{"2001:db8::1", "Destination unreachable: No route", ErrorNetUnreachable, 1, 0},
+		{"2001:db8::1", "Destination unreachable: Administratively prohibited", ErrorAdminProhibited, 1, 1},
+		{"2001:db8::1", "Destination unreachable: Address unreachable", ErrorHostUnreachable, 1, 3},
+		{"2001:db8::1", "Destination unreachable: Port unreachable", ErrorPortUnreachable, 1, 4},
+		{"2001:db8::1", "Packet too big: mtu=1280", ErrorFragmentationNeeded, 2, 0},
+		{"2001:db8::1", "Time exceeded: Hop limit", ErrorTTLExceeded, 3, 0},
+		{"2001:db8::1", "Parameter problem: Wrong header field at 40", ErrorParameterProblem, 4, 0},
+		{"2001:db8::1", "Parameter problem: Unknown header at 6", ErrorParameterProblem, 4, 1},
+		{"2001:db8::1", "Parameter problem: Unknown option at 42", ErrorParameterProblem, 4, 2},
+		// BSD
+		{"10.0.0.1", "Communication prohibited by filter", ErrorAdminProhibited, 3, 13},
+		{"10.0.0.1", "frag needed and DF set (MTU 1400)", ErrorFragmentationNeeded, 3, 4},
+		{"2001:db8::1", "Parameter problem: Erroneous Header pointer = 0x28", ErrorParameterProblem, 4, 0},
+		{"2001:db8::1", "Parameter problem: Unknown Nextheader pointer = 0x06", ErrorParameterProblem, 4, 1},
+		{"2001:db8::1", "Parameter problem: Unrecognized Option pointer = 0x2a", ErrorParameterProblem, 4, 2},
+		{"10.0.0.1", "Host precedence violation", ErrorOther, 3, 14},
+		{"10.0.0.1", "Precedence cutoff in effect", ErrorOther, 3, 15},
+		// Windows
+		{"10.0.0.1", "Destination net unreachable", ErrorNetUnreachable, 3, 0},
+		{"", "Destination host unreachable", ErrorHostUnreachable, 3, 1},
+		{"10.0.0.1", "TTL expired in transit", ErrorTTLExceeded, 11, 0},
+		{"10.0.0.1", "Packet needs to be fragmented but DF set", ErrorFragmentationNeeded, 3, 4},
+		{"2001:db8::1", "Destination host unreachable", ErrorHostUnreachable, 1, 3},
+		{"", "General failure", ErrorUnknown, 0, 0},
+	} {
+		kind, typ, code := classifyError(tc.fromAddress, tc.error)
+		if kind != tc.kind || typ != tc.typ || code != tc.code {
+			t.Errorf("%q: expected %v (type %d, code %d), but got %v (type %d, code %d)", tc.error, tc.kind, tc.typ, tc.code, kind, typ, code)
+		}
+	}
+}
+
+func TestErrorKindString(t *testing.T) {
+	if s := ErrorTTLExceeded.String(); s != "TTL exceeded" {
+		t.Errorf("expected %q, but got %q", "TTL exceeded", s)
+	}
+	if s := ErrorKind(100).String(); s != "ErrorKind(100)" {
+		t.Errorf("expected %q, but got %q", "ErrorKind(100)", s)
+	}
+}
+
+func TestParseMTU(t *testing.T) {
+	for _, tc := range []struct {
+		fromAddress string
+		error       string
+		mtu         uint
+	}{
+		{"10.0.0.1", "Frag needed and DF set (mtu = 1400)", 1400},
+		{"10.0.0.1", "frag needed and DF set (MTU 1492)", 1492},
+		{"2001:db8::1", "Packet too big: mtu=1280", 1280},
+		{"10.0.0.1", "Packet needs to be fragmented but DF set", 0},
+		{"10.0.0.1", "Destination Host Unreachable", 0},
+	} {
+		pr, err := parseReply([]*regexp.Regexp{hostErrorLineRx1}, "From "+tc.fromAddress+" icmp_seq=1 "+tc.error, "")
+		if err != nil {
+			t.Errorf("%q: %v", tc.error, err)
+			continue
+		}
+		if pr.MTU != tc.mtu {
+			t.Errorf("%q: expected MTU %d, but got %d", tc.error, tc.mtu, pr.MTU)
+		}
+	}
+}
+
+func TestParseRedirect(t *testing.T) {
+	for _, tc := range []struct {
+		line     string
+		redirect RedirectType
+		nextHop  string
+	}{
+		{"From 10.0.0.1: icmp_seq=1 Redirect Host(New nexthop: 10.0.0.254)", RedirectHost, "10.0.0.254"},
+		{"From 10.0.0.1: icmp_seq=1 Redirect Type of Service and Network(New nexthop: 10.0.0.254)", RedirectTOSNetwork, "10.0.0.254"},
+		{"92 bytes from 10.0.0.1: Redirect Network(New addr: 10.0.0.254)", RedirectNetwork, "10.0.0.254"},
+		{"From 10.0.0.1 icmp_seq=1 Destination Host Unreachable", RedirectNone, ""},
+	} {
+		pr, err := parseReply([]*regexp.Regexp{hostErrorLineRx3, hostErrorLineRx1, hostErrorLineRx2}, tc.line, "")
+		if err != nil {
+			t.Errorf("%q: %v", tc.line, err)
+			continue
+		}
+		if pr.FromAddress != "10.0.0.1" {
+			t.Errorf("%q: expected from address %q, but got %q", tc.line, "10.0.0.1", pr.FromAddress)
+		}
+		if pr.Redirect != tc.redirect || pr.NextHop != tc.nextHop {
+			t.Errorf("%q: expected redirect %v to %q, but got %v to %q", tc.line, tc.redirect, tc.nextHop, pr.Redirect, pr.NextHop)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// State identifies the section of ping output the next line is expected to belong to.
+type State int
+
+const (
+	// StateHeader expects the "PING ..." header line.
+	StateHeader State = iota
+	// StateReplies expects reply or host error lines, until a blank line or the statistics separator.
+	StateReplies
+	// StateStatsHeader expects the "--- ... ping statistics ---" separator.
+	StateStatsHeader
+	// StateStatsLine1 expects the packet counters line.
+	StateStatsLine1
+	// StateStatsLine2 expects the round-trip summary line.
+	StateStatsLine2
+	// StateDone is reached once the statistics are complete, any further line is ignored.
+	StateDone
+)
+
+var stateNames = []string{
+	StateHeader:      "header",
+	StateReplies:     "replies",
+	StateStatsHeader: "stats header",
+	StateStatsLine1:  "stats line 1",
+	StateStatsLine2:  "stats line 2",
+	StateDone:        "done",
+}
+
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+
+	return stateNames[s]
+}
+
+// LineParser is a push-style parser for ping output: lines are fed to it one at a time,
+// and it walks from the header through the replies to the statistics, accumulating
+// everything in a PingOutput. The dialect of the output is detected from its first line
+// among the registered ones. The zero value is ready to use, with default options.
+type LineParser struct {
+	state  State
+	po     PingOutput
+	opts   options
+	lineNo int
+	// dialect of the output, and the other dialects which detected its header line
+	dialect    Dialect
+	candidates []Dialect
+	// lines parsed since the header, kept while the dialect can still be switched
+	lines []string
+}
+
+// NewLineParser returns a new LineParser expecting a header line.
+func NewLineParser(opts ...Option) *LineParser {
+	return &LineParser{opts: newOptions(opts)}
+}
+
+// Feed parses the next line of output, without its trailing newline, and returns the
+// event it produced; lines that do not complete any part of the output produce EventNone.
+// When a line is rejected the state does not advance and the returned *ParseError reports
+// the state that rejected it. With Lenient, such lines are recorded and produce EventUnrecognized instead.
+// When iputils reports errors without any valid reply, a pipe size line may follow the packet
+// counters, so EventStatistics is only produced by the next line or by Close.
+func (p *LineParser) Feed(line string) (Event, error) {
+	p.lineNo++
+	line = strings.TrimSuffix(line, "\r")
+	ev, err := p.feed(line)
+	if err != nil && p.opts.lenient && isUnrecognized(err) {
+		if line == "" {
+			return Event{}, nil
+		}
+		ul := UnrecognizedLine{Number: p.lineNo, Text: line}
+		p.po.UnrecognizedLines = append(p.po.UnrecognizedLines, ul)
+		p.log(slog.LevelWarn, "skipped unrecognized line", "line", p.lineNo, "state", p.state.String(), "text", line)
+
+		return Event{Kind: EventUnrecognized, Unrecognized: &ul}, nil
+	}
+	if err != nil {
+		p.log(slog.LevelDebug, "rejected line", "line", p.lineNo, "state", p.state.String(), "text", line, "error", err)
+		return Event{}, &ParseError{Line: p.lineNo, Text: line, State: p.state, Err: err}
+	}
+
+	return ev, nil
+}
+
+// isUnrecognized reports whether err was caused by a line not belonging to the expected section,
+// as opposed to a line which was recognized but could not be converted.
+func isUnrecognized(err error) bool {
+	switch err {
+	case ErrHeaderMismatch, ErrUnrecognizedLine, ErrMalformedStatsHeader, ErrMalformedStatsLine1, ErrMalformedStatsLine2:
+		return true
+	}
+
+	return false
+}
+
+func (p *LineParser) feed(line string) (Event, error) {
+	if p.state == StateHeader {
+		return p.feedHeader(line)
+	}
+
+	replies, snapshots := len(p.po.Replies), len(p.po.Snapshots)
+	state, err := p.dialect.ParseLine(p.state, line, &p.po)
+	if err != nil && replies == 0 && isUnrecognized(err) {
+		if switched, ok := p.switchDialect(line); ok {
+			state, err = switched, nil
+		}
+	}
+	if err != nil {
+		return Event{}, err
+	}
+
+	previous := p.state
+	p.state = state
+	if len(p.candidates) != 0 && len(p.po.Replies) == 0 {
+		p.lines = append(p.lines, line)
+	} else {
+		p.lines = nil
+	}
+
+	switch {
+	case len(p.po.Replies) > replies:
+		pr := p.po.Replies[len(p.po.Replies)-1]
+		return Event{Kind: EventReply, Reply: &pr}, nil
+	case len(p.po.Snapshots) > snapshots:
+		ps := p.po.Snapshots[len(p.po.Snapshots)-1]
+		return Event{Kind: EventSnapshot, Snapshot: &ps}, nil
+	case state == StateDone && previous != StateDone:
+		return p.done(), nil
+	}
+
+	return Event{}, nil
+}
+
+// feedHeader detects the dialect of the output from its first line.
+func (p *LineParser) feedHeader(line string) (Event, error) {
+	if line == "" {
+		return Event{}, nil
+	}
+	detected := detectDialects(line)
+	if len(detected) == 0 {
+		return Event{}, ErrHeaderMismatch
+	}
+
+	var err error
+	p.dialect, p.candidates = detected[0], detected[1:]
+	p.state, err = p.dialect.ParseLine(StateHeader, line, &p.po)
+	if err != nil {
+		return Event{}, err
+	}
+	p.lines = []string{line}
+	p.po.Dialect = p.dialect.Name()
+
+	header := p.po
+	return Event{Kind: EventHeader, Header: &header}, nil
+}
+
+// switchDialect retries line with the other dialects which detected the header, as several
+// ping implementations share the same header format; the lines parsed so far are parsed
+// again with the new dialect. This is only possible until the first reply has been parsed.
+func (p *LineParser) switchDialect(line string) (State, bool) {
+	for i, d := range p.candidates {
+		po := PingOutput{UnrecognizedLines: p.po.UnrecognizedLines}
+		state, err := replay(d, p.lines, &po)
+		if err != nil {
+			continue
+		}
+		state, err = d.ParseLine(state, line, &po)
+		if err != nil {
+			continue
+		}
+		// the candidates which failed may still match a later line
+		candidates := append([]Dialect{}, p.candidates[:i]...)
+		p.candidates = append(candidates, p.candidates[i+1:]...)
+
+		po.Dialect = d.Name()
+		p.dialect, p.po = d, po
+		p.log(slog.LevelDebug, "switched dialect", "line", p.lineNo, "dialect", d.Name())
+
+		return state, true
+	}
+
+	return p.state, false
+}
+
+// replay parses lines, starting with the header, with d into po and returns the state of the next line.
+func replay(d Dialect, lines []string, po *PingOutput) (State, error) {
+	state := StateHeader
+	for _, line := range lines {
+		var err error
+		state, err = d.ParseLine(state, line, po)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	return state, nil
+}
+
+// Close signals the end of the output and reports whether it was complete. With AllowPartial,
+// output cut short after the header is accepted and Close returns the synthesized statistics.
+func (p *LineParser) Close() (Event, error) {
+	switch p.state {
+	case StateDone:
+		return Event{}, nil
+	case StateHeader:
+		return Event{}, &ParseError{Line: p.lineNo, State: p.state, Err: ErrNotEnoughLines}
+	case StateStatsLine2:
+		// the round-trip summary is only printed with valid replies
+		if !expectsStatsLine2(&p.po) {
+			return p.done(), nil
+		}
+	}
+	if !p.opts.partial {
+		return Event{}, &ParseError{Line: p.lineNo, State: p.state, Err: ErrNotEnoughLines}
+	}
+
+	// the packet counters are only missing if the output ended before stats line 1
+	if p.state != StateStatsLine2 {
+		if p.po.Stats.IPAddress == "" {
+			p.po.Stats.IPAddress = p.po.Host
+		}
+		countReplies(p.po.Replies, &p.po.Stats)
+	}
+	computeRoundTrip(p.po.Replies, &p.po.Stats)
+	p.po.Stats.Synthesized = true
+	p.log(slog.LevelWarn, "statistics computed from replies", "line", p.lineNo, "state", p.state.String())
+
+	return p.done(), nil
+}
+
+// log reports a parsing anomaly to the configured logger, if any.
+func (p *LineParser) log(level slog.Level, msg string, args ...any) {
+	if p.opts.logger == nil {
+		return
+	}
+	p.opts.logger.Log(context.Background(), level, msg, args...)
+}
+
+// State returns the state the parser is in, i.e. which section the next line is expected to belong to.
+func (p *LineParser) State() State {
+	return p.state
+}
+
+// Output returns everything parsed so far.
+func (p *LineParser) Output() *PingOutput {
+	return &p.po
+}
+
+// done marks the statistics as complete and returns the corresponding event.
+func (p *LineParser) done() Event {
+	p.state = StateDone
+	if err := p.po.CheckDuplicates(); err != nil {
+		p.log(slog.LevelWarn, "inconsistent statistics", "line", p.lineNo, "error", err)
+	}
+
+	stats := p.po.Stats
+	return Event{Kind: EventStatistics, Stats: &stats}
+}
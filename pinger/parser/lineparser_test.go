@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLineParserStates(t *testing.T) {
+	lines := strings.Split(payloads[0], "\n")
+	expected := []struct {
+		kind  EventKind
+		state State
+	}{
+		{EventHeader, StateReplies},
+		{EventReply, StateReplies},
+		{EventReply, StateReplies},
+		{EventReply, StateReplies},
+		{EventNone, StateStatsHeader},
+		{EventNone, StateStatsLine1},
+		{EventNone, StateStatsLine2},
+		{EventStatistics, StateDone},
+		{EventNone, StateDone},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, but got %d", len(expected), len(lines))
+	}
+
+	p := NewLineParser()
+	for i, line := range lines {
+		ev, err := p.Feed(line)
+		if err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if ev.Kind != expected[i].kind {
+			t.Errorf("line %d: expected event %v, but got %v", i+1, expected[i].kind, ev.Kind)
+		}
+		if p.State() != expected[i].state {
+			t.Errorf("line %d: expected state %v, but got %v", i+1, expected[i].state, p.State())
+		}
+	}
+	if _, err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Output().Replies) != 3 {
+		t.Errorf("expected 3 replies, but got %d", len(p.Output().Replies))
+	}
+}
+
+func TestLineParserStatsWithoutReplies(t *testing.T) {
+	testCases := []struct {
+		lines    []string
+		expected []EventKind
+	}{
+		// every packet was lost, nothing can follow the packet counters
+		{
+			[]string{"PING 10.0.0.99 (10.0.0.99) 56(84) bytes of data.", "", "--- 10.0.0.99 ping statistics ---", "3 packets transmitted, 0 received, 100% packet loss, time 2041ms"},
+			[]EventKind{EventHeader, EventNone, EventNone, EventStatistics},
+		},
+		// errors may be followed by the pipe size
+		{
+			[]string{"PING 10.0.0.99 (10.0.0.99) 56(84) bytes of data.", "", "--- 10.0.0.99 ping statistics ---", "4 packets transmitted, 0 received, +3 errors, 100% packet loss, time 31ms", "pipe 4"},
+			[]EventKind{EventHeader, EventNone, EventNone, EventNone, EventStatistics},
+		},
+	}
+
+	for i, tc := range testCases {
+		p := NewLineParser()
+		for j, line := range tc.lines {
+			ev, err := p.Feed(line)
+			if err != nil {
+				t.Fatalf("testcase #%d: line %d: %v", i, j+1, err)
+			}
+			if ev.Kind != tc.expected[j] {
+				t.Errorf("testcase #%d: line %d: expected event %v, but got %v", i, j+1, tc.expected[j], ev.Kind)
+			}
+		}
+		if p.State() != StateDone {
+			t.Errorf("testcase #%d: expected state %v, but got %v", i, StateDone, p.State())
+		}
+	}
+}
+
+func TestLineParserRejectedState(t *testing.T) {
+	testCases := []struct {
+		lines         []string
+		expectedErr   error
+		expectedState State
+	}{
+		{[]string{"PONG"}, ErrHeaderMismatch, StateHeader},
+		{[]string{"ping: unknown host"}, ErrUnknownHost, StateHeader},
+		{[]string{"PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.", "ping: sendmsg: No buffer space available"}, ErrUnrecognizedLine, StateReplies},
+		{[]string{"PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.", "", "statistics"}, ErrMalformedStatsHeader, StateStatsHeader},
+		{[]string{"PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.", "--- 127.0.0.1 ping statistics ---", "3 packets"}, ErrMalformedStatsLine1, StateStatsLine1},
+		{[]string{"PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.", "64 bytes from 127.0.0.1: icmp_seq=1 ttl=64 time=0.026 ms", "--- 127.0.0.1 ping statistics ---", "1 packets transmitted, 1 received, 0% packet loss, time 0ms", "rtt"}, ErrMalformedStatsLine2, StateStatsLine2},
+	}
+
+	for i, tc := range testCases {
+		p := NewLineParser()
+		var err error
+		for _, line := range tc.lines {
+			if _, err = p.Feed(line); err != nil {
+				break
+			}
+		}
+		if !errors.Is(err, tc.expectedErr) {
+			t.Errorf("testcase #%d: expected %v, but got %v", i, tc.expectedErr, err)
+		}
+		if p.State() != tc.expectedState {
+			t.Errorf("testcase #%d: expected state %v, but got %v", i, tc.expectedState, p.State())
+		}
+	}
+}
+
+func TestLineParserIncomplete(t *testing.T) {
+	p := NewLineParser()
+	if _, err := p.Feed("PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Close(); !errors.Is(err, ErrNotEnoughLines) {
+		t.Errorf("expected %v, but got %v", ErrNotEnoughLines, err)
+	}
+}
+
+func TestLineParserLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	p := NewLineParser(WithLogger(logger))
+	p.Feed("PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.")
+	p.Feed("garbage")
+	if !strings.Contains(buf.String(), `msg="rejected line" line=2 state=replies text=garbage`) {
+		t.Errorf("rejected line not logged: %q", buf.String())
+	}
+
+	// without a logger, lines are still rejected
+	p = NewLineParser()
+	_, err := p.Feed("garbage")
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Errorf("expected a *ParseError, but got %v", err)
+	}
+}
+
+func TestLineParserDuplicatesMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	p := NewLineParser(WithLogger(logger))
+	for _, line := range []string{
+		"PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.",
+		"64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.045 ms",
+		"",
+		"--- 10.0.0.1 ping statistics ---",
+		"1 packets transmitted, 1 received, +1 duplicates, 0% packet loss, time 0ms",
+		"rtt min/avg/max/mdev = 0.045/0.045/0.045/0.000 ms",
+	} {
+		if _, err := p.Feed(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := p.Output().CheckDuplicates()
+	if !errors.Is(err, ErrDuplicatesMismatch) {
+		t.Errorf("expected %v, but got %v", ErrDuplicatesMismatch, err)
+	}
+	if !strings.Contains(buf.String(), `msg="inconsistent statistics" line=6`) {
+		t.Errorf("mismatch not logged: %q", buf.String())
+	}
+
+	// quiet output has no replies to flag
+	buf.Reset()
+	p = NewLineParser(WithLogger(logger))
+	for _, line := range []string{
+		"PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.",
+		"",
+		"--- 10.0.0.1 ping statistics ---",
+		"1 packets transmitted, 1 received, +1 duplicates, 0% packet loss, time 0ms",
+		"rtt min/avg/max/mdev = 0.045/0.045/0.045/0.000 ms",
+	} {
+		if _, err := p.Feed(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := p.Output().CheckDuplicates(); err != nil {
+		t.Errorf("expected no error for quiet output, but got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing logged for quiet output, but got %q", buf.String())
+	}
+}
+
+func TestLineParserSwitchDialectAfterUnrecognizedLine(t *testing.T) {
+	p := NewLineParser(Lenient())
+	for _, line := range []string{
+		"PING 10.0.0.1 (10.0.0.1): 56 data bytes",
+		"ping: sendmsg: No buffer space available",
+		"64 bytes from 10.0.0.1: seq=0 ttl=64 time=0.054 ms",
+		"",
+		"--- 10.0.0.1 ping statistics ---",
+		"1 packets transmitted, 1 packets received, 0% packet loss",
+		"round-trip min/avg/max = 0.054/0.054/0.054 ms",
+	} {
+		if _, err := p.Feed(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	po := p.Output()
+	if po.Dialect != "busybox" {
+		t.Errorf("expected dialect busybox, but got %q", po.Dialect)
+	}
+	if len(po.Replies) != 1 {
+		t.Errorf("expected 1 reply, but got %d", len(po.Replies))
+	}
+	if len(po.UnrecognizedLines) != 1 {
+		t.Errorf("expected 1 unrecognized line, but got %d", len(po.UnrecognizedLines))
+	}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import "log/slog"
+
+// Option configures how ping output is parsed.
+type Option func(*options)
+
+type options struct {
+	partial bool
+	lenient bool
+	logger  *slog.Logger
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// AllowPartial accepts output which ends before the statistics are complete, for example
+// because ping was killed; the statistics are then computed from the replies and marked
+// as synthesized.
+func AllowPartial() Option {
+	return func(o *options) {
+		o.partial = true
+	}
+}
+
+// Lenient skips lines which cannot be parsed in the section they appear in, for example
+// interleaved warnings, instead of failing; they are recorded in PingOutput.UnrecognizedLines.
+func Lenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+// WithLogger reports parsing anomalies (rejected and skipped lines, synthesized statistics) to
+// logger; by default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
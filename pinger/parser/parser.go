@@ -3,7 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
-	"log"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -18,6 +18,7 @@ var (
 	ErrMalformedStatsHeader = errors.New("malformed stats header")
 	ErrMalformedStatsLine1  = errors.New("malformed stats line 1")
 	ErrMalformedStatsLine2  = errors.New("malformed stats line 2")
+	ErrDuplicatesMismatch   = errors.New("duplicate replies do not match the statistics")
 )
 
 type ConversionError struct {
@@ -29,38 +30,163 @@ func (ce ConversionError) Error() string {
 	return fmt.Sprintf("%s: %v", ce.Context, ce.Err)
 }
 
+func (ce ConversionError) Unwrap() error {
+	return ce.Err
+}
+
+// ParseError is returned when a line of ping output cannot be parsed, wrapping one of the
+// errors above or a ConversionError.
+type ParseError struct {
+	// Line is the 1-based number of the offending line; for incomplete output it is the number
+	// of lines parsed.
+	Line int
+	// Text is the offending line, empty for incomplete output.
+	Text string
+	// State is the section of output that was being parsed.
+	State State
+	Err   error
+}
+
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("line %d (%s): %v", pe.Line, pe.State, pe.Err)
+}
+
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+const (
+	// ipv4Pattern matches a dotted-quad IPv4 address.
+	ipv4Pattern = `\d+\.\d+\.\d+\.\d+`
+	// ipv6Pattern matches an IPv6 address, including an optional zone index (e.g. fe80::1%eth0).
+	ipv6Pattern = `[0-9a-fA-F]*:[0-9a-fA-F:.]*(?:%[\w.-]+)?`
+	// addressPattern matches either an IPv4 or an IPv6 address.
+	addressPattern = `(?:` + ipv4Pattern + `|` + ipv6Pattern + `)`
+	// hostPattern matches a DNS name or an address.
+	hostPattern = `(?:` + ipv6Pattern + `|[\w.-]+)`
+	// fromPattern matches the replying address, optionally preceded by its reverse-resolved name.
+	fromPattern = `(?:(?P<fromHost>` + hostPattern + `) \((?P<fromAddress>` + addressPattern + `)\)|(?P<fromAddress>` + addressPattern + `))`
+	// resolvedPattern matches the resolved address in a header, which iputils may print
+	// as "name (address)" for IPv6 destinations.
+	resolvedPattern = `(?:` + hostPattern + ` \((?P<resolvedIPAddress>` + addressPattern + `)\)|(?P<resolvedIPAddress>` + addressPattern + `))`
+)
+
 var (
-	headerRx         = regexp.MustCompile(`^PING (?P<host>\d+\.\d+\.\d+\.\d+) \((?P<resolvedIPAddress>\d+\.\d+\.\d+\.\d+)\) (?P<payloadSize>\d+)\((?P<payloadActualSize>\d+)\) bytes of data`)
-	headerRxAlt      = regexp.MustCompile(`^PING (?P<host>\d+\.\d+\.\d+\.\d+) \((?P<resolvedIPAddress>\d+\.\d+\.\d+\.\d+)\): (?P<payloadSize>\d+) data bytes`)
-	lineRx           = regexp.MustCompile(`^(?P<replySize>\d+) bytes from (?P<fromAddress>\d+\.\d+\.\d+\.\d+): icmp_seq=(?P<seqNo>\d+) ttl=(?P<ttl>\d+) time=(?P<time>.*)$`)
-	statsSeparatorRx = regexp.MustCompile(`^--- (?P<IPAddress>\d+\.\d+\.\d+\.\d+) ping statistics ---$`)
-	statsLine1       = regexp.MustCompile(`^(?P<packetsTransmitted>\d+) packets transmitted, (?P<packetsReceived>\d+) (packets )?received,( \+(?P<errors>\d+) errors,)?( \+(?P<duplicates>\d+) duplicates,)?( (?P<packetLoss>\-?\d+)% packet loss)?(, time (?P<time>.*))?( \-\- (?P<warning>.*))?$`)
-	statsLine2       = regexp.MustCompile(`^(rtt|round-trip) min/avg/max/(mdev|stddev) = (?P<min>[^/]+)/(?P<avg>[^/]+)/(?P<max>[^/]+)/(?P<mdev>[^ ]+) (?P<unit>.*)$`)
+	headerRx         = regexp.MustCompile(`^PING (?P<host>` + hostPattern + `) ?\(` + resolvedPattern + `\) (?P<payloadSize>\d+)(?:\((?P<payloadActualSize>\d+)\) bytes of data| data bytes)`)
+	headerRxAlt      = regexp.MustCompile(`^PING (?P<host>` + hostPattern + `) \((?P<resolvedIPAddress>` + addressPattern + `)\): (?P<payloadSize>\d+) data bytes`)
+	headerRx6        = regexp.MustCompile(`^PING6\((?P<payloadActualSize>\d+)=\d+\+\d+\+(?P<payloadSize>\d+) bytes\) (?P<sourceAddress>` + addressPattern + `) --> (?P<host>` + addressPattern + `)$`)
+	lineRx           = regexp.MustCompile(`^(?P<replySize>\d+) bytes from ` + fromPattern + `[:,] icmp_seq=(?P<seqNo>\d+) (?:ttl|hlim)=(?P<ttl>\d+)(?: time=(?P<time>.*))?$`)
+	statsSeparatorRx = regexp.MustCompile(`^--- (?P<IPAddress>` + hostPattern + `) ping6? statistics ---$`)
+	statsLine1       = regexp.MustCompile(`^(?P<packetsTransmitted>\d+) packets transmitted, (?P<packetsReceived>\d+) (packets )?received,( \+(?P<duplicates>\d+) duplicates,)?( \+\d+ corrupted,)?( \+(?P<errors>\d+) errors,)?( (?P<packetLoss>\-?\d+(?:\.\d+)?)% packet loss)?(, time (?P<time>.*))?( \-\- (?P<warning>.*))?$`)
+	statsLine2       = regexp.MustCompile(`^(rtt|round-trip) min/avg/max/(mdev|stddev|std-dev) = (?P<min>[^/]+)/(?P<avg>[^/]+)/(?P<max>[^/]+)/(?P<mdev>[^ ]+) (?P<unit>.*)$`)
 	pipeNo           = regexp.MustCompile(`(?P<unit>[^,]+), pipe (?P<pipeNo>\d+)$`)
-	pipeNoLine       = regexp.MustCompile(`^pipe (?P<pipeNo>\d+)$`)
-	hostErrorLineRx1 = regexp.MustCompile(`^From (?P<fromAddress>\d+\.\d+\.\d+\.\d+) icmp_seq=(?P<seqNo>\d+) (?P<error>.*)$`)
-	hostErrorLineRx2 = regexp.MustCompile(`^(?P<replySize>\d+) bytes from (?P<fromAddress>\d+\.\d+\.\d+\.\d+): (?P<error>.*)$`)
+	pipeNoLine       = regexp.MustCompile(`^(?:pipe (?P<pipeNo>\d+)(?:, ipg/ewma (?P<ipg>[^/]+)/(?P<ewma>[^ ]+) (?P<ipgUnit>\S+))?|ipg/ewma (?P<ipg>[^/]+)/(?P<ewma>[^ ]+) (?P<ipgUnit>\S+))$`)
+	// snapshotRx matches the intermediate summary printed on SIGQUIT, starting with a carriage return
+	snapshotRx       = regexp.MustCompile(`^\r?(?P<packetsReceived>\d+)/(?P<packetsTransmitted>\d+) packets, (?P<packetLoss>\d+)% loss(?:, min/avg/ewma/max = (?P<min>[^/]+)/(?P<avg>[^/]+)/(?P<ewma>[^/]+)/(?P<max>[^ ]+) (?P<unit>\S+))?$`)
+	ipgEwma          = regexp.MustCompile(`(?P<unit>.+), ipg/ewma (?P<ipg>[^/]+)/(?P<ewma>[^ ]+) (?P<ipgUnit>\S+)$`)
+	hostErrorLineRx1 = regexp.MustCompile(`^From ` + fromPattern + ` icmp_seq=(?P<seqNo>\d+) (?P<error>.*)$`)
+	// the error cannot start with "seq=", which would be a BusyBox reply
+	hostErrorLineRx2 = regexp.MustCompile(`^(?P<replySize>\d+) bytes from ` + fromPattern + `: (?P<error>(?:[^s]|s[^e]|se[^q]|seq[^=]).*)$`)
+	// hostErrorLineRx3 matches redirects, reported with a colon after the address
+	hostErrorLineRx3 = regexp.MustCompile(`^From ` + fromPattern + `: icmp_seq=(?P<seqNo>\d+) (?P<error>.*)$`)
+	// floodLineRx matches the progress printed by ping -f: a dot for each request, erased by a backspace for each reply, and E for errors
+	floodLineRx      = regexp.MustCompile(`^[.\x08E]+$`)
+	noAnswerLineRx   = regexp.MustCompile(`^(?P<timeout>no answer yet) for icmp_seq=(?P<seqNo>\d+)$`)
+	unknownHostRx    = regexp.MustCompile(`^ping: unknown host$`)
+	bsdTimeoutLineRx = regexp.MustCompile(`^(?P<timeout>Request timeout) for icmp_seq (?P<seqNo>\d+)$`)
+	bsdUnknownHostRx = regexp.MustCompile(`^ping6?: cannot resolve .*: Unknown host$`)
+	// timestampRx matches the wall-clock time printed before each line by ping -D
+	timestampRx = regexp.MustCompile(`^\[(?P<timestamp>\d+(?:\.\d+)?)\] `)
+)
+
+// BusyBox ping output
+var (
+	busyboxLineRx        = regexp.MustCompile(`^(?P<replySize>\d+) bytes from ` + fromPattern + `: seq=(?P<seqNo>\d+) ttl=(?P<ttl>\d+) time=(?P<time>.*)$`)
+	busyboxStatsLine2    = regexp.MustCompile(`^round-trip min/avg/max = (?P<min>[^/]+)/(?P<avg>[^/]+)/(?P<max>[^ ]+) (?P<unit>.*)$`)
+	busyboxUnknownHostRx = regexp.MustCompile(`^ping: bad address '.*'$`)
 )
 
+// Windows ping.exe output
+var (
+	windowsHeaderRx         = regexp.MustCompile(`^Pinging (?P<host>` + hostPattern + `)(?: \[(?P<resolvedIPAddress>` + addressPattern + `)\])? with (?P<payloadSize>\d+) bytes of data:$`)
+	windowsUnknownHostRx    = regexp.MustCompile(`^Ping request could not find host .*\.`)
+	windowsLineRx           = regexp.MustCompile(`^Reply from ` + fromPattern + `: (?:bytes=(?P<replySize>\d+)(?: \(sent \d+\))? )?time(?P<timeOp>[=<])(?P<time>\d+ms)(?: TTL=(?P<ttl>\d+))?$`)
+	windowsHostErrorLineRx1 = regexp.MustCompile(`^Reply from ` + fromPattern + `: (?P<error>Destination (?:host|net|protocol|port) unreachable|Destination prohibited|Communication administratively prohibited|TTL expired (?:in transit|during reassembly)|Packet needs to be fragmented but DF set|Source quench received|Parameter problem|Bad route)\.?$`)
+	windowsHostErrorLineRx2 = regexp.MustCompile(`^(?P<error>Destination (?:host|net) unreachable|(?:PING: )?(?:[Tt]ransmit failed\. )?General failure)\.$`)
+	windowsTimeoutLineRx    = regexp.MustCompile(`^(?P<timeout>Request timed out)\.$`)
+	windowsStatsSeparatorRx = regexp.MustCompile(`^Ping statistics for (?P<IPAddress>` + hostPattern + `):$`)
+	windowsStatsLine1       = regexp.MustCompile(`^\s+Packets: Sent = (?P<packetsTransmitted>\d+), Received = (?P<packetsReceived>\d+), Lost = \d+ \((?P<packetLoss>\d+)% loss\),?$`)
+	windowsStatsLine2Header = regexp.MustCompile(`^Approximate round trip times in milli-seconds:$`)
+	windowsStatsLine2       = regexp.MustCompile(`^\s+Minimum = (?P<min>\d+ms), Maximum = (?P<max>\d+ms), Average = (?P<avg>\d+ms)$`)
+)
+
+// UnknownDuration is used for the durations which the ping implementation does not report.
+const UnknownDuration time.Duration = -1
+
 // PingOutput contains the whole ping operation output.
 type PingOutput struct {
+	// Dialect is the name of the dialect the output was parsed with.
+	Dialect           string
 	Host              string
 	ResolvedIPAddress string
 	PayloadSize       uint
 	PayloadActualSize uint
 	Replies           []PingReply
-	Stats             PingStatistics
+	// Snapshots are the intermediate summaries printed among the replies, in order.
+	Snapshots []PingSnapshot
+	Stats     PingStatistics
+	// UnrecognizedLines contains the lines skipped when parsing leniently.
+	UnrecognizedLines []UnrecognizedLine
+}
+
+// UnrecognizedLine is a line of output which could not be parsed.
+type UnrecognizedLine struct {
+	// Number is the 1-based line number.
+	Number int
+	Text   string
 }
 
 // PingReply contains an individual ping reply line.
 type PingReply struct {
 	Size           uint
 	FromAddress    string
+	FromHost       string
 	SequenceNumber uint
 	TTL            uint
 	Time           time.Duration
 	Error          string
-	Duplicate      bool
+	// ErrorKind classifies Error, with the ICMP type and code it was reported with
+	// (ICMPv6 ones for IPv6 addresses); they are only set when Error is.
+	ErrorKind ErrorKind
+	ICMPType  uint8
+	ICMPCode  uint8
+	// MTU is the next-hop MTU advertised with ErrorFragmentationNeeded, by the router at FromAddress.
+	MTU uint
+	// Redirect and NextHop are the type of route and the new gateway advertised with ErrorRedirect.
+	Redirect RedirectType
+	NextHop  string
+	// TTLExceeded is set when the TTL of the request expired on its way, FromAddress being
+	// the router at that hop, e.g. when pinging with a low TTL (ping -t).
+	TTLExceeded bool
+	Duplicate   bool
+	// Timeout is set for requests which were not answered in time.
+	Timeout bool
+	// Timestamp is the wall-clock time printed before the line with ping -D, zero otherwise.
+	Timestamp time.Time
+}
+
+// PingSnapshot contains an intermediate summary, printed by iputils on SIGQUIT while running.
+type PingSnapshot struct {
+	// Replies is the number of replies preceding the snapshot in PingOutput.Replies.
+	Replies            int
+	PacketsTransmitted uint
+	PacketsReceived    uint
+	PacketLossPercent  uint8
+	// the round-trip times are only reported once a reply was received
+	RoundTripMin     time.Duration
+	RoundTripAverage time.Duration
+	RoundTripEWMA    time.Duration
+	RoundTripMax     time.Duration
 }
 
 // PingStatistics contains the statistics of the whole ping operation.
@@ -69,244 +195,430 @@ type PingStatistics struct {
 	PacketsTransmitted uint
 	PacketsReceived    uint
 	Errors             uint
-	PacketLossPercent  uint8
+	Duplicates         uint
+	// PacketLossPercent is PacketLoss truncated to an integer, or 0 when it is negative.
+	PacketLossPercent uint8
+	// PacketLoss is the percentage of requests without a reply, e.g. 16.7 for macOS "16.7% packet loss".
+	PacketLoss float64
+	// NegativePacketLoss is set when iputils reported more replies than requests as a negative
+	// loss, because of duplicate or forged replies.
+	NegativePacketLoss bool
 	Time               time.Duration
 	RoundTripMin       time.Duration
 	RoundTripAverage   time.Duration
 	RoundTripMax       time.Duration
+	// RoundTripDeviation is UnknownDuration when the summary does not include it.
 	RoundTripDeviation time.Duration
-	Warning            string
+	// InterPacketGap and RoundTripEWMA are the average interval between requests and the
+	// exponentially weighted moving average of the round-trip time, reported by iputils in
+	// adaptive and flood modes.
+	InterPacketGap time.Duration
+	RoundTripEWMA  time.Duration
+	Warning        string
+	// Pipe is the highest number of requests outstanding at once, reported by iputils when above 1.
+	Pipe uint
+	// Synthesized is set when ping did not print (all of) the statistics, and they
+	// were computed from the replies instead.
+	Synthesized bool
 }
 
+// matchAsMap returns the named groups of rx in s, or nil if rx does not match s.
 func matchAsMap(rx *regexp.Regexp, s string) map[string]string {
 	m := rx.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
 	result := make(map[string]string)
-	if len(m) != 0 {
-		for i, name := range rx.SubexpNames()[1:] {
-			result[name] = m[i+1]
+	for i, name := range rx.SubexpNames()[1:] {
+		// a name can be used in several alternatives, keep the one that matched
+		if _, ok := result[name]; ok && m[i+1] == "" {
+			continue
 		}
+		result[name] = m[i+1]
 	}
 
 	return result
 }
 
-// Parse will parse the specified ping output and return all the information in a a PingOutput object.
-func Parse(s string) (*PingOutput, error) {
-	var po PingOutput
-
-	// separate full output text into lines
-	lines := strings.Split(s, "\n")
-	if len(lines) < 4 {
-		if len(lines) != 0 {
-			if lines[0] == "ping: unknown host" {
-				return nil, ErrUnknownHost
-			}
+// matchFirst returns the named groups of the first of rxs matching s, or nil if none does.
+func matchFirst(rxs []*regexp.Regexp, s string) map[string]string {
+	for _, rx := range rxs {
+		if result := matchAsMap(rx, s); result != nil {
+			return result
+		}
+	}
+
+	return nil
+}
+
+// matchesAny reports whether any of rxs matches s.
+func matchesAny(rxs []*regexp.Regexp, s string) bool {
+	for _, rx := range rxs {
+		if rx.MatchString(s) {
+			return true
 		}
-		return nil, ErrNotEnoughLines
 	}
 
-	result := matchAsMap(headerRx, lines[0])
-	if len(result) == 0 {
-		result = matchAsMap(headerRxAlt, lines[0])
-		if len(result) == 0 {
-			return nil, ErrHeaderMismatch
+	return false
+}
+
+// Parse will parse the specified ping output and return all the information in a a PingOutput object.
+func Parse(s string, opts ...Option) (*PingOutput, error) {
+	p := NewLineParser(opts...)
+	// separate full output text into lines, the final newline does not start another one
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		if _, err := p.Feed(line); err != nil {
+			return nil, err
 		}
 	}
+	if _, err := p.Close(); err != nil {
+		return nil, err
+	}
+
+	return p.Output(), nil
+}
+
+// parseHeader parses the first line of ping output into the header fields of po.
+func parseHeader(rxs []*regexp.Regexp, line string, po *PingOutput) error {
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return ErrHeaderMismatch
+	}
 	po.Host = result["host"]
 	po.ResolvedIPAddress = result["resolvedIPAddress"]
-	payloadSize, err := strconv.ParseUint(result["payloadSize"], 10, 64)
-	if err != nil {
-		return nil, ConversionError{"payloadSize", err}
+	if po.ResolvedIPAddress == "" {
+		// BSD ping6 and Windows (for addresses) only print the destination address
+		po.ResolvedIPAddress = po.Host
+	}
+	if v, ok := result["payloadSize"]; ok && len(v) != 0 {
+		payloadSize, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return ConversionError{"payloadSize", err}
+		}
+		po.PayloadSize = uint(payloadSize)
 	}
-	po.PayloadSize = uint(payloadSize)
 
-	if v, ok := result["payloadActualSize"]; ok {
+	if v, ok := result["payloadActualSize"]; ok && len(v) != 0 {
 		payloadActualSize, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
-			return nil, ConversionError{"payloadActualSize", err}
+			return ConversionError{"payloadActualSize", err}
 		}
 		po.PayloadActualSize = uint(payloadActualSize)
 	}
 
-	// start parsing replies
-	var last int
-	for i, line := range lines[1:] {
-		if line == "" {
-			last = i + 2
-			break
-		}
-		var pr PingReply
+	return nil
+}
 
-		// remove DUP postfix (if any)
-		if strings.HasSuffix(line, " (DUP!)") {
-			pr.Duplicate = true
-			line = line[:len(line)-7]
-		}
+// parseReply parses a single ping reply or host error line.
+func parseReply(rxs []*regexp.Regexp, line, timeUnit string) (PingReply, error) {
+	var pr PingReply
 
-		result = matchAsMap(lineRx, line)
-		if len(result) == 0 {
-			// try to match a host error line
-			result = matchAsMap(hostErrorLineRx1, line)
-			if len(result) == 0 {
-				result = matchAsMap(hostErrorLineRx2, line)
-				if len(result) == 0 {
-					// some ping outputs have a new line separator, others don't
-					result = matchAsMap(statsSeparatorRx, line)
-					if len(result) != 0 {
-						last = i + 1
-						break
-					}
-
-					return nil, ErrUnrecognizedLine
-				}
-			}
+	// remove DUP postfix (if any)
+	if strings.HasSuffix(line, " (DUP!)") {
+		pr.Duplicate = true
+		line = line[:len(line)-7]
+	}
+
+	// remove -D timestamp prefix (if any)
+	if m := timestampRx.FindStringSubmatch(line); m != nil {
+		var err error
+		pr.Timestamp, err = parseTimestamp(m[1])
+		if err != nil {
+			return pr, ConversionError{"timestamp", err}
 		}
+		line = line[len(m[0]):]
+	}
 
-		if v, ok := result["replySize"]; ok && len(v) != 0 {
-			replySize, err := strconv.ParseUint(v, 10, 64)
-			if err != nil {
-				return nil, ConversionError{"replySize", err}
-			}
-			pr.Size = uint(replySize)
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return pr, ErrUnrecognizedLine
+	}
+
+	if v, ok := result["replySize"]; ok && len(v) != 0 {
+		replySize, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return pr, ConversionError{"replySize", err}
 		}
-		pr.FromAddress = result["fromAddress"]
-		pr.Error = result["error"]
+		pr.Size = uint(replySize)
+	}
+	pr.FromAddress = result["fromAddress"]
+	pr.FromHost = result["fromHost"]
+	pr.Error = result["error"]
+	if pr.Error != "" {
+		pr.ErrorKind, pr.ICMPType, pr.ICMPCode = classifyError(pr.FromAddress, pr.Error)
+	}
+	if v := matchAsMap(mtuRx, pr.Error)["mtu"]; pr.ErrorKind == ErrorFragmentationNeeded && v != "" {
+		mtu, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return pr, ConversionError{"mtu", err}
+		}
+		pr.MTU = uint(mtu)
+	}
+	if pr.ErrorKind == ErrorRedirect {
+		pr.Redirect = redirectType(pr.ICMPType, pr.ICMPCode)
+		pr.NextHop = matchAsMap(nextHopRx, pr.Error)["nextHop"]
+	}
+	pr.TTLExceeded = pr.ErrorKind == ErrorTTLExceeded
+	pr.Timeout = result["timeout"] != ""
 
-		if v, ok := result["seqNo"]; ok && len(v) != 0 {
-			replySeqNo, err := strconv.ParseUint(v, 10, 64)
-			if err != nil {
-				return nil, ConversionError{"reply seqNo", err}
-			}
-			pr.SequenceNumber = uint(replySeqNo)
+	if v, ok := result["seqNo"]; ok && len(v) != 0 {
+		replySeqNo, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return pr, ConversionError{"reply seqNo", err}
 		}
+		pr.SequenceNumber = uint(replySeqNo)
+	}
 
-		if v, ok := result["ttl"]; ok && len(v) != 0 {
-			replyTTL, err := strconv.ParseUint(v, 10, 64)
-			if err != nil {
-				return nil, ConversionError{"ttl", err}
-			}
-			pr.TTL = uint(replyTTL)
+	if v, ok := result["ttl"]; ok && len(v) != 0 {
+		replyTTL, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return pr, ConversionError{"ttl", err}
 		}
+		pr.TTL = uint(replyTTL)
+	}
 
-		if v, ok := result["time"]; ok && len(v) != 0 {
-			pr.Time, err = time.ParseDuration(strings.Replace(v, " ", "", -1))
-			if err != nil {
-				return nil, ConversionError{"ping reply time", err}
-			}
+	// Windows reports "time<1ms", which is counted as zero as in its own statistics
+	if v, ok := result["time"]; ok && len(v) != 0 && result["timeOp"] != "<" {
+		var err error
+		pr.Time, err = parseDuration(v, timeUnit)
+		if err != nil {
+			return pr, ConversionError{"ping reply time", err}
 		}
+	}
 
-		// cleanup 'result', as it is checked after the loop
-		result = map[string]string{}
+	return pr, nil
+}
+
+// parseSnapshot parses an intermediate summary line.
+func parseSnapshot(rxs []*regexp.Regexp, line string) (PingSnapshot, error) {
+	var ps PingSnapshot
 
-		po.Replies = append(po.Replies, pr)
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return ps, ErrUnrecognizedLine
 	}
+	packetsTransmitted, err := strconv.ParseUint(result["packetsTransmitted"], 10, 64)
+	if err != nil {
+		return ps, ConversionError{"snapshot packetsTransmitted", err}
+	}
+	ps.PacketsTransmitted = uint(packetsTransmitted)
+	packetsReceived, err := strconv.ParseUint(result["packetsReceived"], 10, 64)
+	if err != nil {
+		return ps, ConversionError{"snapshot packetsReceived", err}
+	}
+	ps.PacketsReceived = uint(packetsReceived)
+	packetLoss, err := strconv.ParseUint(result["packetLoss"], 10, 8)
+	if err != nil {
+		return ps, ConversionError{"snapshot packetLoss", err}
+	}
+	ps.PacketLossPercent = uint8(packetLoss)
 
-	if len(result) == 0 {
-		if last >= len(lines) {
-			return nil, ErrNotEnoughLines
-		}
-		// parse header
-		result = matchAsMap(statsSeparatorRx, lines[last])
-		if len(result) == 0 {
-			return nil, ErrMalformedStatsHeader
+	if result["min"] == "" {
+		return ps, nil
+	}
+	for _, f := range []struct {
+		context string
+		d       *time.Duration
+	}{
+		{"min", &ps.RoundTripMin},
+		{"avg", &ps.RoundTripAverage},
+		{"ewma", &ps.RoundTripEWMA},
+		{"max", &ps.RoundTripMax},
+	} {
+		*f.d, err = parseDuration(result[f.context], result["unit"])
+		if err != nil {
+			return ps, ConversionError{"snapshot " + f.context, err}
 		}
 	}
-	po.Stats.IPAddress = result["IPAddress"]
 
-	// parse stats line 1
-	last++
-	result = matchAsMap(statsLine1, lines[last])
-	if len(result) == 0 {
-		return nil, ErrMalformedStatsLine1
+	return ps, nil
+}
+
+// parseStatsHeader parses the line separating the replies from the statistics.
+func parseStatsHeader(rxs []*regexp.Regexp, line string, stats *PingStatistics) error {
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return ErrMalformedStatsHeader
+	}
+	stats.IPAddress = result["IPAddress"]
+
+	return nil
+}
+
+// parseStatsLine1 parses the packet counters line of the statistics.
+func parseStatsLine1(rxs []*regexp.Regexp, line, timeUnit string, stats *PingStatistics) error {
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return ErrMalformedStatsLine1
 	}
 	packetsTransmitted, err := strconv.ParseUint(result["packetsTransmitted"], 10, 64)
 	if err != nil {
-		return nil, ConversionError{"packetsTransmitted", err}
+		return ConversionError{"packetsTransmitted", err}
 	}
-	po.Stats.PacketsTransmitted = uint(packetsTransmitted)
+	stats.PacketsTransmitted = uint(packetsTransmitted)
 
 	// a negative packets received count will trigger a conversion error here
 	packetsReceived, err := strconv.ParseUint(result["packetsReceived"], 10, 64)
 	if err != nil {
-		return nil, ConversionError{"packetsReceived", err}
+		return ConversionError{"packetsReceived", err}
+	}
+	stats.PacketsReceived = uint(packetsReceived)
+
+	if v, ok := result["duplicates"]; ok && len(v) != 0 {
+		duplicates, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return ConversionError{"duplicates", err}
+		}
+		stats.Duplicates = uint(duplicates)
 	}
-	po.Stats.PacketsReceived = uint(packetsReceived)
 
 	if v, ok := result["errors"]; ok && len(v) != 0 {
 		errCount, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
-			return nil, ConversionError{"stats errors", err}
+			return ConversionError{"stats errors", err}
 		}
-		po.Stats.Errors = uint(errCount)
+		stats.Errors = uint(errCount)
 	}
 
 	if v, ok := result["packetLoss"]; ok && len(v) != 0 {
-		packetLossPcent, err := strconv.ParseUint(v, 10, 64)
+		stats.PacketLoss, err = strconv.ParseFloat(v, 64)
 		if err != nil {
-			return nil, ConversionError{"packetLoss", err}
+			return ConversionError{"packetLoss", err}
+		}
+		// iputils reports more replies than requests as a negative loss
+		stats.NegativePacketLoss = stats.PacketLoss < 0
+		stats.PacketLossPercent = 0
+		if !stats.NegativePacketLoss {
+			stats.PacketLossPercent = uint8(math.Min(stats.PacketLoss, 100))
 		}
-		po.Stats.PacketLossPercent = uint8(packetLossPcent)
 	} else {
-		po.Stats.Warning = result["warning"]
+		stats.Warning = result["warning"]
 	}
 
 	if v, ok := result["time"]; ok && len(v) != 0 {
-		po.Stats.Time, err = time.ParseDuration(v)
+		stats.Time, err = parseDuration(v, timeUnit)
 		if err != nil {
-			return nil, ConversionError{"stats time", err}
+			return ConversionError{"stats time", err}
 		}
 	}
 
-	validReplies := 0
-	// check if a summary second line of stats is expected
-	for _, pr := range po.Replies {
-		if pr.Error != `` {
-			continue
+	return nil
+}
+
+// parseStatsLine2 parses the round-trip summary line of the statistics.
+func parseStatsLine2(rxs []*regexp.Regexp, line, timeUnit string, stats *PingStatistics) error {
+	result := matchFirst(rxs, line)
+	if result == nil {
+		return ErrMalformedStatsLine2
+	}
+	if _, ok := result["min"]; !ok {
+		// iputils prints the suffixes alone when the round-trip times were not measured
+		if v := result["pipeNo"]; v != "" {
+			if err := parsePipe(v, stats); err != nil {
+				return err
+			}
 		}
-		validReplies++
+		return parseIPG(result, stats)
 	}
-	if validReplies == 0 {
-		return &po, nil
+
+	unit := result["unit"]
+	// the suffixes are printed in the order pipe, ipg/ewma
+	if im := matchAsMap(ipgEwma, unit); len(im) != 0 {
+		unit = im["unit"]
+		if err := parseIPG(im, stats); err != nil {
+			return err
+		}
+	}
+	pm := matchAsMap(pipeNo, unit)
+	if len(pm) != 0 {
+		unit = pm["unit"]
+		if err := parsePipe(pm["pipeNo"], stats); err != nil {
+			return err
+		}
+	}
+	if unit == "" {
+		unit = timeUnit
 	}
 
-	// parse stats line 2
-	last++
-	result = matchAsMap(statsLine2, lines[last])
-	if len(result) == 0 {
-		result = matchAsMap(pipeNoLine, lines[last])
-		if len(result) != 0 {
-			// ignore pipe number
-			return &po, nil
+	var err error
+	stats.RoundTripMin, err = parseDuration(result["min"], unit)
+	if err != nil {
+		return ConversionError{"rtt", err}
+	}
+	stats.RoundTripAverage, err = parseDuration(result["avg"], unit)
+	if err != nil {
+		return ConversionError{"avg", err}
+	}
+	stats.RoundTripMax, err = parseDuration(result["max"], unit)
+	if err != nil {
+		return ConversionError{"max", err}
+	}
+	// BusyBox and Windows do not report the deviation
+	if v, ok := result["mdev"]; ok && len(v) != 0 {
+		stats.RoundTripDeviation, err = parseDuration(v, unit)
+		if err != nil {
+			return ConversionError{"mdev", err}
 		}
+	} else {
+		stats.RoundTripDeviation = UnknownDuration
+	}
 
-		log.Println("faulty:", lines[last])
+	return nil
+}
 
-		return nil, ErrMalformedStatsLine2
+// parsePipe parses the maximum number of outstanding requests reported by iputils.
+func parsePipe(v string, stats *PingStatistics) error {
+	pipe, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return ConversionError{"pipe", err}
 	}
+	stats.Pipe = uint(pipe)
 
-	unit := result["unit"]
-	pm := matchAsMap(pipeNo, unit)
-	if len(pm) != 0 {
-		unit = pm["unit"]
-		// pipe number is ignored
+	return nil
+}
+
+// parseIPG parses the inter-packet gap and the smoothed round-trip time reported by iputils, if any.
+func parseIPG(result map[string]string, stats *PingStatistics) error {
+	if result["ipg"] == "" {
+		return nil
 	}
 
-	po.Stats.RoundTripMin, err = time.ParseDuration(result["min"] + unit)
+	var err error
+	stats.InterPacketGap, err = parseDuration(result["ipg"], result["ipgUnit"])
 	if err != nil {
-		return nil, ConversionError{"rtt", err}
+		return ConversionError{"ipg", err}
 	}
-	po.Stats.RoundTripAverage, err = time.ParseDuration(result["avg"] + unit)
+	stats.RoundTripEWMA, err = parseDuration(result["ewma"], result["ipgUnit"])
 	if err != nil {
-		return nil, ConversionError{"avg", err}
+		return ConversionError{"ewma", err}
+	}
+
+	return nil
+}
+
+// parseDuration parses a duration printed by ping, with unit appended if it has none.
+func parseDuration(v, unit string) (time.Duration, error) {
+	v = strings.Replace(v, " ", "", -1)
+	if v != "" && strings.IndexByte("0123456789.", v[len(v)-1]) != -1 {
+		v += unit
 	}
-	po.Stats.RoundTripMax, err = time.ParseDuration(result["max"] + unit)
+
+	return time.ParseDuration(v)
+}
+
+// parseTimestamp parses a Unix time in seconds with a fractional part, as printed by ping -D.
+func parseTimestamp(v string) (time.Time, error) {
+	secs, frac, _ := strings.Cut(v, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
 	if err != nil {
-		return nil, ConversionError{"max", err}
+		return time.Time{}, err
 	}
-	po.Stats.RoundTripDeviation, err = time.ParseDuration(result["mdev"] + unit)
+	// the fraction is scaled to nanoseconds, any further digit is dropped
+	frac = (frac + "000000000")[:9]
+	nsec, err := strconv.ParseInt(frac, 10, 64)
 	if err != nil {
-		return nil, ConversionError{"mdev", err}
+		return time.Time{}, err
 	}
 
-	return &po, nil
+	return time.Unix(sec, nsec), nil
 }
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -10,14 +13,15 @@ var (
 	expectedTestCases = []PingOutput{
 		// 0
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `127.0.0.1`,
 			ResolvedIPAddress: `127.0.0.1`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Replies: []PingReply{
-				PingReply{64, `127.0.0.1`, 1, 64, 26 * time.Microsecond, "", false},
-				PingReply{64, `127.0.0.1`, 2, 64, 21 * time.Microsecond, "", false},
-				PingReply{64, `127.0.0.1`, 3, 64, 31 * time.Microsecond, "", false},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 26 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 21 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 3, TTL: 64, Time: 31 * time.Microsecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `127.0.0.1`,
@@ -32,19 +36,21 @@ var (
 		},
 		// 1
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `172.17.0.1`,
 			ResolvedIPAddress: `172.17.0.1`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Replies: []PingReply{
-				PingReply{64, `172.17.0.1`, 1, 64, 98 * time.Microsecond, "", false},
-				PingReply{64, `172.17.0.1`, 2, 64, 90 * time.Microsecond, "", false},
+				PingReply{Size: 64, FromAddress: `172.17.0.1`, SequenceNumber: 1, TTL: 64, Time: 98 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.1`, SequenceNumber: 2, TTL: 64, Time: 90 * time.Microsecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.1`,
 				PacketsTransmitted: 3,
 				PacketsReceived:    2,
 				PacketLossPercent:  33,
+				PacketLoss:         33,
 				Time:               10292 * time.Millisecond,
 				RoundTripMin:       90 * time.Microsecond,
 				RoundTripMax:       98 * time.Microsecond,
@@ -54,32 +60,37 @@ var (
 		},
 		// 2
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `172.17.0.1`,
 			ResolvedIPAddress: `172.17.0.1`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Stats: PingStatistics{
+				IPAddress:          `172.17.0.1`,
 				PacketsTransmitted: 3,
 				PacketsReceived:    0,
 				PacketLossPercent:  100,
+				PacketLoss:         100,
 				Time:               10205 * time.Millisecond,
 			},
 		},
 		// 3
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `172.17.0.2`,
 			ResolvedIPAddress: `172.17.0.2`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Replies: []PingReply{
-				PingReply{64, `172.17.0.2`, 4, 63, 286 * time.Millisecond, "", false},
-				PingReply{64, `172.17.0.2`, 5, 63, 111 * time.Millisecond, "", false},
+				PingReply{Size: 64, FromAddress: `172.17.0.2`, SequenceNumber: 4, TTL: 63, Time: 286 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.2`, SequenceNumber: 5, TTL: 63, Time: 111 * time.Millisecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.2`,
 				PacketsTransmitted: 5,
 				PacketsReceived:    2,
 				PacketLossPercent:  60,
+				PacketLoss:         60,
 				Time:               4055 * time.Millisecond,
 				RoundTripMin:       111409 * time.Microsecond,
 				RoundTripMax:       286063 * time.Microsecond,
@@ -89,19 +100,22 @@ var (
 		},
 		// 4
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `172.17.0.2`,
 			ResolvedIPAddress: `172.17.0.2`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Replies: []PingReply{
-				PingReply{64, `172.17.0.2`, 4, 63, 286 * time.Millisecond, "", false},
-				PingReply{64, `172.17.0.2`, 5, 63, 111 * time.Millisecond, "", false},
+				PingReply{Size: 64, FromAddress: `172.17.0.2`, SequenceNumber: 4, TTL: 63, Time: 286 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.2`, SequenceNumber: 5, TTL: 63, Time: 111 * time.Millisecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.2`,
 				PacketsTransmitted: 5,
 				PacketsReceived:    2,
 				PacketLossPercent:  60,
+				PacketLoss:         60,
+				Pipe:               2,
 				Time:               4055 * time.Millisecond,
 				RoundTripMin:       111409 * time.Microsecond,
 				RoundTripMax:       286063 * time.Microsecond,
@@ -111,12 +125,13 @@ var (
 		},
 		// 5
 		PingOutput{
+			Dialect:           DialectIPutils,
 			Host:              `172.17.0.3`,
 			ResolvedIPAddress: `172.17.0.3`,
 			PayloadSize:       56,
 			PayloadActualSize: 84,
 			Replies: []PingReply{
-				PingReply{0, `93.184.216.34`, 2, 0, 0, "Destination Host Unreachable", false},
+				PingReply{FromAddress: `93.184.216.34`, SequenceNumber: 2, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.3`,
@@ -124,18 +139,21 @@ var (
 				PacketsTransmitted: 4,
 				PacketsReceived:    0,
 				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Pipe:               3,
 				Time:               3055 * time.Millisecond,
 			},
 		},
 		// 6
 		PingOutput{
+			Dialect:           DialectBSD,
 			Host:              `127.0.0.1`,
 			ResolvedIPAddress: `127.0.0.1`,
 			PayloadSize:       56,
 			Replies: []PingReply{
-				PingReply{64, `127.0.0.1`, 0, 64, 61 * time.Microsecond, "", false},
-				PingReply{64, `127.0.0.1`, 1, 64, 57 * time.Microsecond, "", false},
-				PingReply{64, `127.0.0.1`, 2, 64, 108 * time.Microsecond, "", false},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 0, TTL: 64, Time: 61 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 57 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `127.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 108 * time.Microsecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `127.0.0.1`,
@@ -149,6 +167,7 @@ var (
 		},
 		// 7
 		PingOutput{
+			Dialect:           DialectBSD,
 			Host:              `172.17.0.4`,
 			ResolvedIPAddress: `172.17.0.4`,
 			PayloadSize:       56,
@@ -157,26 +176,30 @@ var (
 				PacketsTransmitted: 6,
 				PacketsReceived:    0,
 				PacketLossPercent:  100,
+				PacketLoss:         100,
 			},
 		},
 		// 8
 		PingOutput{
+			Dialect:           DialectBSD,
 			Host:              `172.17.0.5`,
 			ResolvedIPAddress: `172.17.0.5`,
 			PayloadSize:       56,
 			Replies: []PingReply{
-				PingReply{64, `172.17.0.5`, 0, 61, 67758 * time.Microsecond, "", false},
-				PingReply{64, `172.17.0.5`, 1, 61, 104863 * time.Microsecond, "", false},
-				PingReply{64, `172.17.0.5`, 2, 61, 78562 * time.Microsecond, "", false},
-				PingReply{64, `172.17.0.5`, 2, 61, 96818 * time.Microsecond, "", true},
-				PingReply{64, `172.17.0.5`, 3, 61, 71488 * time.Microsecond, "", false},
-				PingReply{64, `172.17.0.5`, 4, 61, 80193 * time.Microsecond, "", false},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 0, TTL: 61, Time: 67758 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 1, TTL: 61, Time: 104863 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 2, TTL: 61, Time: 78562 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 2, TTL: 61, Time: 96818 * time.Microsecond, Duplicate: true},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 3, TTL: 61, Time: 71488 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `172.17.0.5`, SequenceNumber: 4, TTL: 61, Time: 80193 * time.Microsecond},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.5`,
 				PacketsTransmitted: 6,
 				PacketsReceived:    5,
+				Duplicates:         1,
 				PacketLossPercent:  16,
+				PacketLoss:         16,
 				RoundTripMin:       67758 * time.Microsecond,
 				RoundTripMax:       104863 * time.Microsecond,
 				RoundTripAverage:   83280 * time.Microsecond,
@@ -185,13 +208,14 @@ var (
 		},
 		// 9
 		PingOutput{
+			Dialect:           DialectBSD,
 			Host:              `172.17.0.6`,
 			ResolvedIPAddress: `172.17.0.6`,
 			PayloadSize:       56,
 			Replies: []PingReply{
-				PingReply{92, `93.184.216.34`, 0, 0, 0, "Destination Host Unreachable", false},
-				PingReply{92, `93.184.216.34`, 0, 0, 0, "Destination Host Unreachable", false},
-				PingReply{92, `93.184.216.34`, 0, 0, 0, "Destination Host Unreachable", false},
+				PingReply{Size: 92, FromAddress: `93.184.216.34`, SequenceNumber: 0, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+				PingReply{Size: 92, FromAddress: `93.184.216.34`, SequenceNumber: 0, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+				PingReply{Size: 92, FromAddress: `93.184.216.34`, SequenceNumber: 0, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
 			},
 			Stats: PingStatistics{
 				IPAddress:          `172.17.0.6`,
@@ -199,10 +223,12 @@ var (
 				PacketsTransmitted: 6,
 				PacketsReceived:    0,
 				PacketLossPercent:  100,
+				PacketLoss:         100,
 			},
 		},
 		// 10
 		PingOutput{
+			Dialect:           DialectBSD,
 			Host:              `172.17.0.7`,
 			ResolvedIPAddress: `172.17.0.7`,
 			PayloadSize:       56,
@@ -222,6 +248,7 @@ var (
 				PacketsTransmitted: 16,
 				PacketsReceived:    24,
 				PacketLossPercent:  0,
+				PacketLoss:         0,
 				RoundTripMin:       152070 * time.Microsecond,
 				RoundTripMax:       449303 * time.Microsecond,
 				RoundTripAverage:   289144 * time.Microsecond,
@@ -229,6 +256,782 @@ var (
 				Warning:            "somebody is printing forged packets!",
 			},
 		},
+		// 11
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `::1`,
+			ResolvedIPAddress: `::1`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `::1`, SequenceNumber: 1, TTL: 64, Time: 45 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `::1`, SequenceNumber: 2, TTL: 64, Time: 62 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `::1`, SequenceNumber: 3, TTL: 64, Time: 58 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `::1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				Time:               2041 * time.Millisecond,
+				RoundTripMin:       45 * time.Microsecond,
+				RoundTripMax:       62 * time.Microsecond,
+				RoundTripAverage:   55 * time.Microsecond,
+				RoundTripDeviation: 7 * time.Microsecond,
+			},
+		},
+		// 12
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `fe80::1%eth0`,
+			ResolvedIPAddress: `fe80::1%eth0`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `fe80::1%eth0`, SequenceNumber: 1, TTL: 64, Time: 412 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `fe80::1%eth0`, SequenceNumber: 2, TTL: 64, Time: 387 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `fe80::1%eth0`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1001 * time.Millisecond,
+				RoundTripMin:       387 * time.Microsecond,
+				RoundTripMax:       412 * time.Microsecond,
+				RoundTripAverage:   399 * time.Microsecond,
+				RoundTripDeviation: 12 * time.Microsecond,
+			},
+		},
+		// 13
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `2001:db8::10`,
+			ResolvedIPAddress: `2001:db8::10`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{FromAddress: `2001:db8::1`, SequenceNumber: 1, Error: "Destination unreachable: Address unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 1, ICMPCode: 3},
+				PingReply{FromAddress: `2001:db8::1`, SequenceNumber: 2, Error: "Destination unreachable: Address unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 1, ICMPCode: 3},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `2001:db8::10`,
+				Errors:             2,
+				PacketsTransmitted: 3,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Pipe:               3,
+				Time:               2030 * time.Millisecond,
+			},
+		},
+		// 14
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `::1`,
+			ResolvedIPAddress: `::1`,
+			PayloadSize:       8,
+			PayloadActualSize: 56,
+			Replies: []PingReply{
+				PingReply{Size: 16, FromAddress: `::1`, SequenceNumber: 0, TTL: 64, Time: 71 * time.Microsecond},
+				PingReply{Size: 16, FromAddress: `::1`, SequenceNumber: 1, TTL: 64, Time: 126 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `::1`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				RoundTripMin:       71 * time.Microsecond,
+				RoundTripMax:       126 * time.Microsecond,
+				RoundTripAverage:   99 * time.Microsecond,
+				RoundTripDeviation: 28 * time.Microsecond,
+			},
+		},
+		// 15
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `example.com`,
+			ResolvedIPAddress: `93.184.216.34`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `93.184.216.34`, FromHost: `example.com`, SequenceNumber: 1, TTL: 56, Time: 11600 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `93.184.216.34`, FromHost: `example.com`, SequenceNumber: 2, TTL: 56, Time: 11900 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `example.com`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1002 * time.Millisecond,
+				RoundTripMin:       11612 * time.Microsecond,
+				RoundTripMax:       11901 * time.Microsecond,
+				RoundTripAverage:   11756 * time.Microsecond,
+				RoundTripDeviation: 144 * time.Microsecond,
+			},
+		},
+		// 16
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `localhost`,
+			ResolvedIPAddress: `::1`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `::1`, FromHost: `localhost`, SequenceNumber: 1, TTL: 64, Time: 29 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `::1`, FromHost: `localhost`, SequenceNumber: 2, TTL: 64, Time: 41 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `localhost`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1013 * time.Millisecond,
+				RoundTripMin:       29 * time.Microsecond,
+				RoundTripMax:       41 * time.Microsecond,
+				RoundTripAverage:   35 * time.Microsecond,
+				RoundTripDeviation: 6 * time.Microsecond,
+			},
+		},
+		// 17
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `example.com`,
+			ResolvedIPAddress: `93.184.216.34`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `93.184.216.34`, SequenceNumber: 0, TTL: 56, Time: 11632 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `93.184.216.34`, SequenceNumber: 1, TTL: 56, Time: 11880 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `example.com`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				RoundTripMin:       11632 * time.Microsecond,
+				RoundTripMax:       11880 * time.Microsecond,
+				RoundTripAverage:   11756 * time.Microsecond,
+				RoundTripDeviation: 124 * time.Microsecond,
+			},
+		},
+		// 18
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.99`,
+			ResolvedIPAddress: `10.0.0.99`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.0.0.1`, FromHost: `gateway`, SequenceNumber: 1, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.99`,
+				Errors:             1,
+				PacketsTransmitted: 2,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Time:               1024 * time.Millisecond,
+			},
+		},
+		// 19
+		PingOutput{
+			Dialect:           DialectWindows,
+			Host:              `8.8.8.8`,
+			ResolvedIPAddress: `8.8.8.8`,
+			PayloadSize:       32,
+			Replies: []PingReply{
+				PingReply{Size: 32, FromAddress: `8.8.8.8`, SequenceNumber: 0, TTL: 117, Time: 14 * time.Millisecond},
+				PingReply{Size: 32, FromAddress: `8.8.8.8`, SequenceNumber: 0, TTL: 117, Time: 13 * time.Millisecond},
+				PingReply{Size: 32, FromAddress: `8.8.8.8`, SequenceNumber: 0, TTL: 117, Time: 15 * time.Millisecond},
+				PingReply{Size: 32, FromAddress: `8.8.8.8`, SequenceNumber: 0, TTL: 117, Time: 14 * time.Millisecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `8.8.8.8`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    4,
+				RoundTripMin:       13 * time.Millisecond,
+				RoundTripMax:       15 * time.Millisecond,
+				RoundTripAverage:   14 * time.Millisecond,
+				RoundTripDeviation: UnknownDuration,
+			},
+		},
+		// 20
+		PingOutput{
+			Dialect:           DialectWindows,
+			Host:              `router.lan`,
+			ResolvedIPAddress: `192.168.1.1`,
+			PayloadSize:       32,
+			Replies: []PingReply{
+				PingReply{Size: 32, FromAddress: `192.168.1.1`, SequenceNumber: 0, TTL: 64},
+				PingReply{SequenceNumber: 0, Timeout: true},
+				PingReply{Size: 32, FromAddress: `192.168.1.1`, SequenceNumber: 0, TTL: 64, Time: time.Millisecond},
+				PingReply{Size: 32, FromAddress: `192.168.1.1`, SequenceNumber: 0, TTL: 64},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `192.168.1.1`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    3,
+				PacketLossPercent:  25,
+				PacketLoss:         25,
+				RoundTripMax:       time.Millisecond,
+				RoundTripDeviation: UnknownDuration,
+			},
+		},
+		// 21
+		PingOutput{
+			Dialect:           DialectWindows,
+			Host:              `10.0.0.99`,
+			ResolvedIPAddress: `10.0.0.99`,
+			PayloadSize:       32,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.0.0.5`, SequenceNumber: 0, Error: "Destination host unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+				PingReply{SequenceNumber: 0, Timeout: true},
+				PingReply{SequenceNumber: 0, Timeout: true},
+				PingReply{FromAddress: `10.0.0.5`, SequenceNumber: 0, Error: "Destination host unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.99`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    2,
+				PacketLossPercent:  50,
+				PacketLoss:         50,
+			},
+		},
+		// 22
+		PingOutput{
+			Dialect:           DialectBusyBox,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 0, TTL: 64, Time: 54 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 59 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 70 * time.Microsecond, Duplicate: true},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 61 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				Duplicates:         1,
+				RoundTripMin:       54 * time.Microsecond,
+				RoundTripMax:       70 * time.Microsecond,
+				RoundTripAverage:   61 * time.Microsecond,
+				RoundTripDeviation: UnknownDuration,
+			},
+		},
+		// 23, BusyBox output cannot be told apart from BSD without replies
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `10.0.0.2`,
+			ResolvedIPAddress: `10.0.0.2`,
+			PayloadSize:       56,
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.2`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+			},
+		},
+		// 24, ping -D
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 45 * time.Microsecond, Timestamp: time.Unix(1697041234, 123456000)},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 51 * time.Microsecond, Timestamp: time.Unix(1697041235, 124012000)},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 139 * time.Microsecond, Duplicate: true, Timestamp: time.Unix(1697041235, 124100000)},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 3, TTL: 64, Time: 48 * time.Microsecond, Timestamp: time.Unix(1697041236, 131877000)},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				Duplicates:         1,
+				Time:               2003 * time.Millisecond,
+				RoundTripMin:       45 * time.Microsecond,
+				RoundTripMax:       139 * time.Microsecond,
+				RoundTripAverage:   70 * time.Microsecond,
+				RoundTripDeviation: 39 * time.Microsecond,
+			},
+		},
+		// 25, ping -D with host errors
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.99`,
+			ResolvedIPAddress: `10.0.0.99`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.0.0.254`, SequenceNumber: 1, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1, Timestamp: time.Unix(1697041300, 512000)},
+				PingReply{FromAddress: `10.0.0.254`, SequenceNumber: 2, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1, Timestamp: time.Unix(1697041301, 530000)},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.99`,
+				Errors:             2,
+				PacketsTransmitted: 2,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Time:               1001 * time.Millisecond,
+			},
+		},
+		// 26, ping -O
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 412 * time.Microsecond},
+				PingReply{SequenceNumber: 2, Timeout: true},
+				PingReply{SequenceNumber: 3, Timeout: true},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 4, TTL: 64, Time: 398 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    2,
+				PacketLossPercent:  50,
+				PacketLoss:         50,
+				Time:               3046 * time.Millisecond,
+				RoundTripMin:       398 * time.Microsecond,
+				RoundTripMax:       412 * time.Microsecond,
+				RoundTripAverage:   405 * time.Microsecond,
+				RoundTripDeviation: 7 * time.Microsecond,
+			},
+		},
+		// 27, macOS
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `192.168.1.20`,
+			ResolvedIPAddress: `192.168.1.20`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 0, TTL: 64, Time: 3512 * time.Microsecond},
+				PingReply{SequenceNumber: 1, Timeout: true},
+				PingReply{SequenceNumber: 2, Timeout: true},
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 3, TTL: 64, Time: 4108 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `192.168.1.20`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    2,
+				PacketLossPercent:  50,
+				PacketLoss:         50,
+				RoundTripMin:       3512 * time.Microsecond,
+				RoundTripMax:       4108 * time.Microsecond,
+				RoundTripAverage:   3810 * time.Microsecond,
+				RoundTripDeviation: 298 * time.Microsecond,
+			},
+		},
+		// 28, macOS without any reply
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `192.168.1.21`,
+			ResolvedIPAddress: `192.168.1.21`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{SequenceNumber: 0, Timeout: true},
+				PingReply{SequenceNumber: 1, Timeout: true},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `192.168.1.21`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+			},
+		},
+		// 29, path MTU discovery
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.1.0.1`,
+			ResolvedIPAddress: `10.1.0.1`,
+			PayloadSize:       1472,
+			PayloadActualSize: 1500,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.0.0.1`, SequenceNumber: 1, Error: "Frag needed and DF set (mtu = 1400)", ErrorKind: ErrorFragmentationNeeded, ICMPType: 3, ICMPCode: 4, MTU: 1400},
+				PingReply{FromAddress: `10.0.0.1`, SequenceNumber: 2, Error: "Frag needed and DF set (mtu = 1400)", ErrorKind: ErrorFragmentationNeeded, ICMPType: 3, ICMPCode: 4, MTU: 1400},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.1.0.1`,
+				Errors:             2,
+				PacketsTransmitted: 2,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Time:               1002 * time.Millisecond,
+			},
+		},
+		// 30, redirects
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `192.168.2.10`,
+			ResolvedIPAddress: `192.168.2.10`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{FromAddress: `192.168.1.1`, SequenceNumber: 1, Error: "Redirect Host(New nexthop: 192.168.1.254)", ErrorKind: ErrorRedirect, ICMPType: 5, ICMPCode: 1, Redirect: RedirectHost, NextHop: `192.168.1.254`},
+				PingReply{Size: 64, FromAddress: `192.168.2.10`, SequenceNumber: 1, TTL: 63, Time: 912 * time.Microsecond},
+				PingReply{FromAddress: `192.168.1.1`, SequenceNumber: 2, Error: "Redirect Network(New nexthop: 192.168.1.254)", ErrorKind: ErrorRedirect, ICMPType: 5, ICMPCode: 0, Redirect: RedirectNetwork, NextHop: `192.168.1.254`},
+				PingReply{Size: 64, FromAddress: `192.168.2.10`, SequenceNumber: 2, TTL: 63, Time: 874 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `192.168.2.10`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1001 * time.Millisecond,
+				RoundTripMin:       874 * time.Microsecond,
+				RoundTripMax:       912 * time.Microsecond,
+				RoundTripAverage:   893 * time.Microsecond,
+				RoundTripDeviation: 19 * time.Microsecond,
+			},
+		},
+		// 31, ping -t 1
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `8.8.8.8`,
+			ResolvedIPAddress: `8.8.8.8`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.1.1.1`, SequenceNumber: 1, Error: "Time to live exceeded", ErrorKind: ErrorTTLExceeded, ICMPType: 11, ICMPCode: 0, TTLExceeded: true},
+				PingReply{FromAddress: `10.1.1.1`, SequenceNumber: 2, Error: "Time to live exceeded", ErrorKind: ErrorTTLExceeded, ICMPType: 11, ICMPCode: 0, TTLExceeded: true},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `8.8.8.8`,
+				Errors:             2,
+				PacketsTransmitted: 2,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Time:               1001 * time.Millisecond,
+			},
+		},
+		// 32, ping -i 1
+		PingOutput{
+			Dialect:           DialectWindows,
+			Host:              `8.8.8.8`,
+			ResolvedIPAddress: `8.8.8.8`,
+			PayloadSize:       32,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.1.1.1`, SequenceNumber: 0, Error: "TTL expired in transit", ErrorKind: ErrorTTLExceeded, ICMPType: 11, ICMPCode: 0, TTLExceeded: true},
+				PingReply{FromAddress: `10.1.1.1`, SequenceNumber: 0, Error: "TTL expired in transit", ErrorKind: ErrorTTLExceeded, ICMPType: 11, ICMPCode: 0, TTLExceeded: true},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `8.8.8.8`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+			},
+		},
+		// 33, more replies than requests
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `172.16.11.34`,
+			ResolvedIPAddress: `172.16.11.34`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 1, TTL: 60, Time: 216 * time.Millisecond},
+				PingReply{Size: 78, FromAddress: `172.16.0.89`, SequenceNumber: 12, TTL: 60, Time: 138 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 2, TTL: 60, Time: 215 * time.Millisecond},
+				PingReply{Size: 78, FromAddress: `172.16.0.89`, SequenceNumber: 13, TTL: 60, Time: 138 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 3, TTL: 60, Time: 215 * time.Millisecond},
+				PingReply{Size: 78, FromAddress: `172.16.0.89`, SequenceNumber: 14, TTL: 60, Time: 139 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 4, TTL: 60, Time: 214 * time.Millisecond},
+				PingReply{Size: 78, FromAddress: `172.16.0.89`, SequenceNumber: 15, TTL: 60, Time: 139 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 5, TTL: 60, Time: 183 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 6, TTL: 60, Time: 181 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 7, TTL: 60, Time: 180 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 8, TTL: 60, Time: 179 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 9, TTL: 60, Time: 187 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 10, TTL: 60, Time: 175 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 11, TTL: 60, Time: 174 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 12, TTL: 60, Time: 183 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 13, TTL: 60, Time: 223 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 14, TTL: 60, Time: 181 * time.Millisecond},
+				PingReply{Size: 64, FromAddress: `172.16.11.34`, SequenceNumber: 15, TTL: 60, Time: 180 * time.Millisecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `172.16.11.34`,
+				PacketsTransmitted: 15,
+				PacketsReceived:    19,
+				PacketLoss:         -26,
+				NegativePacketLoss: true,
+				Time:               14015 * time.Millisecond,
+				RoundTripMin:       138678 * time.Microsecond,
+				RoundTripMax:       223131 * time.Microsecond,
+				RoundTripAverage:   181624 * time.Microsecond,
+				RoundTripDeviation: 26863 * time.Microsecond,
+			},
+		},
+		// 34, macOS
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `192.168.1.20`,
+			ResolvedIPAddress: `192.168.1.20`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 0, TTL: 64, Time: 3512 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 1, TTL: 64, Time: 3877 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 2, TTL: 64, Time: 4108 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 3, TTL: 64, Time: 3650 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `192.168.1.20`, SequenceNumber: 5, TTL: 64, Time: 3799 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `192.168.1.20`,
+				PacketsTransmitted: 6,
+				PacketsReceived:    5,
+				PacketLossPercent:  16,
+				PacketLoss:         16.7,
+				RoundTripMin:       3512 * time.Microsecond,
+				RoundTripMax:       4108 * time.Microsecond,
+				RoundTripAverage:   3789 * time.Microsecond,
+				RoundTripDeviation: 200 * time.Microsecond,
+			},
+		},
+		// 35, ping -A
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64, Time: 52 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 61 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 3, TTL: 64, Time: 47 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				Time:               2 * time.Millisecond,
+				RoundTripMin:       47 * time.Microsecond,
+				RoundTripMax:       61 * time.Microsecond,
+				RoundTripAverage:   53 * time.Microsecond,
+				RoundTripDeviation: 5 * time.Microsecond,
+				InterPacketGap:     1024 * time.Microsecond,
+				RoundTripEWMA:      54 * time.Microsecond,
+			},
+		},
+		// 36, ping -A with several requests outstanding
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.2`,
+			ResolvedIPAddress: `10.0.0.2`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.2`, SequenceNumber: 1, TTL: 63, Time: 20100 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.2`, SequenceNumber: 2, TTL: 63, Time: 19800 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.2`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Pipe:               2,
+				Time:               10 * time.Millisecond,
+				RoundTripMin:       19874 * time.Microsecond,
+				RoundTripMax:       20112 * time.Microsecond,
+				RoundTripAverage:   19993 * time.Microsecond,
+				RoundTripDeviation: 119 * time.Microsecond,
+				InterPacketGap:     10212 * time.Microsecond,
+				RoundTripEWMA:      20082 * time.Microsecond,
+			},
+		},
+		// 37, SIGQUIT while running
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Replies: []PingReply{
+				PingReply{FromAddress: `10.0.0.254`, SequenceNumber: 1, Error: "Destination Host Unreachable", ErrorKind: ErrorHostUnreachable, ICMPType: 3, ICMPCode: 1},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64, Time: 30 * time.Microsecond},
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 3, TTL: 64, Time: 90 * time.Microsecond},
+			},
+			Snapshots: []PingSnapshot{
+				PingSnapshot{Replies: 1, PacketsTransmitted: 1, PacketsReceived: 0, PacketLossPercent: 100},
+				PingSnapshot{Replies: 3, PacketsTransmitted: 3, PacketsReceived: 2, PacketLossPercent: 33, RoundTripMin: 30 * time.Microsecond, RoundTripAverage: 60 * time.Microsecond, RoundTripEWMA: 37 * time.Microsecond, RoundTripMax: 90 * time.Microsecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				Errors:             1,
+				PacketsTransmitted: 3,
+				PacketsReceived:    2,
+				PacketLossPercent:  33,
+				PacketLoss:         33.3333,
+				Time:               2031 * time.Millisecond,
+				RoundTripMin:       30 * time.Microsecond,
+				RoundTripMax:       90 * time.Microsecond,
+				RoundTripAverage:   60 * time.Microsecond,
+				RoundTripDeviation: 30 * time.Microsecond,
+			},
+		},
+		// 38, ping -q
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				Time:               2003 * time.Millisecond,
+				RoundTripMin:       41 * time.Microsecond,
+				RoundTripMax:       61 * time.Microsecond,
+				RoundTripAverage:   50 * time.Microsecond,
+				RoundTripDeviation: 8 * time.Microsecond,
+			},
+		},
+		// 39, macOS ping -q
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `192.168.1.20`,
+			ResolvedIPAddress: `192.168.1.20`,
+			PayloadSize:       56,
+			Stats: PingStatistics{
+				IPAddress:          `192.168.1.20`,
+				PacketsTransmitted: 4,
+				PacketsReceived:    3,
+				PacketLossPercent:  25,
+				PacketLoss:         25,
+				RoundTripMin:       3512 * time.Microsecond,
+				RoundTripMax:       4108 * time.Microsecond,
+				RoundTripAverage:   3810 * time.Microsecond,
+				RoundTripDeviation: 244 * time.Microsecond,
+			},
+		},
+		// 40, BusyBox ping -q, told apart from BSD by its round-trip summary
+		PingOutput{
+			Dialect:           DialectBusyBox,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 3,
+				PacketsReceived:    3,
+				RoundTripMin:       54 * time.Microsecond,
+				RoundTripMax:       70 * time.Microsecond,
+				RoundTripAverage:   61 * time.Microsecond,
+				RoundTripDeviation: UnknownDuration,
+			},
+		},
+		// 41, ping -f
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 7,
+				PacketsReceived:    5,
+				PacketLossPercent:  28,
+				PacketLoss:         28.5714,
+				Time:               12 * time.Millisecond,
+				RoundTripMin:       14 * time.Microsecond,
+				RoundTripMax:       31 * time.Microsecond,
+				RoundTripAverage:   20 * time.Microsecond,
+				RoundTripDeviation: 6 * time.Microsecond,
+				InterPacketGap:     2 * time.Millisecond,
+				RoundTripEWMA:      22 * time.Microsecond,
+			},
+		},
+		// 42, ping -f without any reply
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.99`,
+			ResolvedIPAddress: `10.0.0.99`,
+			PayloadSize:       56,
+			PayloadActualSize: 84,
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.99`,
+				Errors:             3,
+				PacketsTransmitted: 4,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+				Time:               31 * time.Millisecond,
+				Pipe:               4,
+			},
+		},
+		// 43, BSD error with an equal sign
+		PingOutput{
+			Dialect:           DialectBSD,
+			Host:              `10.0.0.9`,
+			ResolvedIPAddress: `10.0.0.9`,
+			PayloadSize:       56,
+			Replies: []PingReply{
+				PingReply{Size: 92, FromAddress: `10.0.0.1`, SequenceNumber: 0, Error: "Parameter problem: pointer = 0x14", ErrorKind: ErrorParameterProblem, ICMPType: 12, ICMPCode: 0},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.9`,
+				PacketsTransmitted: 1,
+				PacketsReceived:    0,
+				PacketLossPercent:  100,
+				PacketLoss:         100,
+			},
+		},
+		// 44, ping -A with a payload too small to carry timestamps
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       4,
+			PayloadActualSize: 32,
+			Replies: []PingReply{
+				PingReply{Size: 12, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64},
+				PingReply{Size: 12, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1 * time.Millisecond,
+				InterPacketGap:     512 * time.Microsecond,
+			},
+		},
+		// 45, Windows reply truncated to 64 bytes by a router
+		PingOutput{
+			Dialect:           DialectWindows,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       1472,
+			Replies: []PingReply{
+				PingReply{Size: 64, FromAddress: `10.0.0.1`, SequenceNumber: 0, TTL: 64, Time: time.Millisecond},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 1,
+				PacketsReceived:    1,
+				RoundTripMin:       time.Millisecond,
+				RoundTripMax:       time.Millisecond,
+				RoundTripAverage:   time.Millisecond,
+				RoundTripDeviation: UnknownDuration,
+			},
+		},
+		// 46, ping with a payload too small to carry timestamps
+		PingOutput{
+			Dialect:           DialectIPutils,
+			Host:              `10.0.0.1`,
+			ResolvedIPAddress: `10.0.0.1`,
+			PayloadSize:       4,
+			PayloadActualSize: 32,
+			Replies: []PingReply{
+				PingReply{Size: 12, FromAddress: `10.0.0.1`, SequenceNumber: 1, TTL: 64},
+				PingReply{Size: 12, FromAddress: `10.0.0.1`, SequenceNumber: 2, TTL: 64},
+			},
+			Stats: PingStatistics{
+				IPAddress:          `10.0.0.1`,
+				PacketsTransmitted: 2,
+				PacketsReceived:    2,
+				Time:               1001 * time.Millisecond,
+			},
+		},
 	}
 	payloads = []string{
 		// 0
@@ -346,27 +1149,216 @@ round-trip min/avg/max/stddev = 67.758/83.280/104.863/13.297 ms
 16 packets transmitted, 24 packets received, -- somebody is printing forged packets!
 round-trip min/avg/max/stddev = 152.070/289.144/449.303/86.309 ms
 `,
-	}
+		// 11
+		`PING ::1 (::1) 56 data bytes
+64 bytes from ::1: icmp_seq=1 ttl=64 time=0.045 ms
+64 bytes from ::1: icmp_seq=2 ttl=64 time=0.062 ms
+64 bytes from ::1: icmp_seq=3 ttl=64 time=0.058 ms
 
-	failedPayloads = map[string]error{
-		`PING 172.17.0.7 (172.17.0.7): 64 data bytes
-72 bytes from 172.17.0.7: icmp_seq=0 ttl=61 time=140.850 ms
-72 bytes from 172.17.0.7: icmp_seq=1 ttl=61 time=177.935 ms
-72 bytes from 172.17.0.7: icmp_seq=2 ttl=61 time=138.084 ms
-72 bytes from 172.17.0.7: icmp_seq=3 ttl=61 time=168.642 ms
-72 bytes from 172.17.0.7: icmp_seq=4 ttl=61 time=166.699 ms
-72 bytes from 172.17.0.7: icmp_seq=5 ttl=61 time=167.026 ms
-72 bytes from 172.17.0.7: icmp_seq=6 ttl=61 time=136.036 ms
-72 bytes from 172.17.0.7: icmp_seq=7 ttl=61 time=145.995 ms
-72 bytes from 172.17.0.7: icmp_seq=8 ttl=61 time=145.516 ms
-72 bytes from 172.17.0.7: icmp_seq=9 ttl=61 time=143.324 ms
-72 bytes from 172.17.0.7: icmp_seq=10 ttl=61 time=162.485 ms
-72 bytes from 172.17.0.7: icmp_seq=11 ttl=61 time=161.856 ms
-`: ErrNotEnoughLines,
-		`ping: unknown host
-`: ErrUnknownHost,
-	}
-	failedPayloadsByErrorString = map[string]string{
+--- ::1 ping statistics ---
+3 packets transmitted, 3 received, 0% packet loss, time 2041ms
+rtt min/avg/max/mdev = 0.045/0.055/0.062/0.007 ms
+`,
+		// 12
+		`PING fe80::1%eth0(fe80::1%eth0) 56 data bytes
+64 bytes from fe80::1%eth0: icmp_seq=1 ttl=64 time=0.412 ms
+64 bytes from fe80::1%eth0: icmp_seq=2 ttl=64 time=0.387 ms
+
+--- fe80::1%eth0 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1001ms
+rtt min/avg/max/mdev = 0.387/0.399/0.412/0.012 ms
+`,
+		// 13
+		`PING 2001:db8::10(2001:db8::10) 56 data bytes
+From 2001:db8::1 icmp_seq=1 Destination unreachable: Address unreachable
+From 2001:db8::1 icmp_seq=2 Destination unreachable: Address unreachable
+
+--- 2001:db8::10 ping statistics ---
+3 packets transmitted, 0 received, +2 errors, 100% packet loss, time 2030ms
+pipe 3
+`,
+		// 14
+		`PING6(56=40+8+8 bytes) ::1 --> ::1
+16 bytes from ::1, icmp_seq=0 hlim=64 time=0.071 ms
+16 bytes from ::1, icmp_seq=1 hlim=64 time=0.126 ms
+
+--- ::1 ping6 statistics ---
+2 packets transmitted, 2 packets received, 0.0% packet loss
+round-trip min/avg/max/std-dev = 0.071/0.099/0.126/0.028 ms
+`,
+		// 15
+		`PING example.com (93.184.216.34) 56(84) bytes of data.
+64 bytes from example.com (93.184.216.34): icmp_seq=1 ttl=56 time=11.6 ms
+64 bytes from example.com (93.184.216.34): icmp_seq=2 ttl=56 time=11.9 ms
+
+--- example.com ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1002ms
+rtt min/avg/max/mdev = 11.612/11.756/11.901/0.144 ms
+`,
+		// 16
+		`PING localhost(localhost (::1)) 56 data bytes
+64 bytes from localhost (::1): icmp_seq=1 ttl=64 time=0.029 ms
+64 bytes from localhost (::1): icmp_seq=2 ttl=64 time=0.041 ms
+
+--- localhost ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1013ms
+rtt min/avg/max/mdev = 0.029/0.035/0.041/0.006 ms
+`,
+		// 17
+		`PING example.com (93.184.216.34): 56 data bytes
+64 bytes from 93.184.216.34: icmp_seq=0 ttl=56 time=11.632 ms
+64 bytes from 93.184.216.34: icmp_seq=1 ttl=56 time=11.880 ms
+--- example.com ping statistics ---
+2 packets transmitted, 2 packets received, 0% packet loss
+round-trip min/avg/max/stddev = 11.632/11.756/11.880/0.124 ms
+`,
+		// 18
+		`PING 10.0.0.99 (10.0.0.99) 56(84) bytes of data.
+From gateway (10.0.0.1) icmp_seq=1 Destination Host Unreachable
+
+--- 10.0.0.99 ping statistics ---
+2 packets transmitted, 0 received, +1 errors, 100% packet loss, time 1024ms
+`,
+		// 19
+		crlf(`
+Pinging 8.8.8.8 with 32 bytes of data:
+Reply from 8.8.8.8: bytes=32 time=14ms TTL=117
+Reply from 8.8.8.8: bytes=32 time=13ms TTL=117
+Reply from 8.8.8.8: bytes=32 time=15ms TTL=117
+Reply from 8.8.8.8: bytes=32 time=14ms TTL=117
+
+Ping statistics for 8.8.8.8:
+    Packets: Sent = 4, Received = 4, Lost = 0 (0% loss),
+Approximate round trip times in milli-seconds:
+    Minimum = 13ms, Maximum = 15ms, Average = 14ms
+`),
+		// 20
+		crlf(`
+Pinging router.lan [192.168.1.1] with 32 bytes of data:
+Reply from 192.168.1.1: bytes=32 time<1ms TTL=64
+Request timed out.
+Reply from 192.168.1.1: bytes=32 time=1ms TTL=64
+Reply from 192.168.1.1: bytes=32 time<1ms TTL=64
+
+Ping statistics for 192.168.1.1:
+    Packets: Sent = 4, Received = 3, Lost = 1 (25% loss),
+Approximate round trip times in milli-seconds:
+    Minimum = 0ms, Maximum = 1ms, Average = 0ms
+`),
+		// 21
+		crlf(`
+Pinging 10.0.0.99 with 32 bytes of data:
+Reply from 10.0.0.5: Destination host unreachable.
+Request timed out.
+Request timed out.
+Reply from 10.0.0.5: Destination host unreachable.
+
+Ping statistics for 10.0.0.99:
+    Packets: Sent = 4, Received = 2, Lost = 2 (50% loss),
+`),
+		// 22
+		`PING 10.0.0.1 (10.0.0.1): 56 data bytes
+64 bytes from 10.0.0.1: seq=0 ttl=64 time=0.054 ms
+64 bytes from 10.0.0.1: seq=1 ttl=64 time=0.059 ms
+64 bytes from 10.0.0.1: seq=1 ttl=64 time=0.070 ms (DUP!)
+64 bytes from 10.0.0.1: seq=2 ttl=64 time=0.061 ms
+
+--- 10.0.0.1 ping statistics ---
+3 packets transmitted, 3 packets received, +1 duplicates, 0% packet loss
+round-trip min/avg/max = 0.054/0.061/0.070 ms
+`,
+		// 23
+		`PING 10.0.0.2 (10.0.0.2): 56 data bytes
+
+--- 10.0.0.2 ping statistics ---
+3 packets transmitted, 0 packets received, 100% packet loss
+`,
+		// 24
+		`PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.
+[1697041234.123456] 64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.045 ms
+[1697041235.124012] 64 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=0.051 ms
+[1697041235.124100] 64 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=0.139 ms (DUP!)
+[1697041236.131877] 64 bytes from 10.0.0.1: icmp_seq=3 ttl=64 time=0.048 ms
+
+--- 10.0.0.1 ping statistics ---
+3 packets transmitted, 3 received, +1 duplicates, 0% packet loss, time 2003ms
+rtt min/avg/max/mdev = 0.045/0.070/0.139/0.039 ms
+`,
+		// 25
+		`PING 10.0.0.99 (10.0.0.99) 56(84) bytes of data.
+[1697041300.000512] From 10.0.0.254 icmp_seq=1 Destination Host Unreachable
+[1697041301.000530] From 10.0.0.254 icmp_seq=2 Destination Host Unreachable
+
+--- 10.0.0.99 ping statistics ---
+2 packets transmitted, 0 received, +2 errors, 100% packet loss, time 1001ms
+`,
+		// 26
+		`PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.
+64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.412 ms
+no answer yet for icmp_seq=2
+no answer yet for icmp_seq=3
+64 bytes from 10.0.0.1: icmp_seq=4 ttl=64 time=0.398 ms
+
+--- 10.0.0.1 ping statistics ---
+4 packets transmitted, 2 received, 50% packet loss, time 3046ms
+rtt min/avg/max/mdev = 0.398/0.405/0.412/0.007 ms
+`,
+		// 27
+		`PING 192.168.1.20 (192.168.1.20): 56 data bytes
+64 bytes from 192.168.1.20: icmp_seq=0 ttl=64 time=3.512 ms
+Request timeout for icmp_seq 1
+Request timeout for icmp_seq 2
+64 bytes from 192.168.1.20: icmp_seq=3 ttl=64 time=4.108 ms
+
+--- 192.168.1.20 ping statistics ---
+4 packets transmitted, 2 packets received, 50.0% packet loss
+round-trip min/avg/max/stddev = 3.512/3.810/4.108/0.298 ms
+`,
+		// 28
+		`PING 192.168.1.21 (192.168.1.21): 56 data bytes
+Request timeout for icmp_seq 0
+Request timeout for icmp_seq 1
+
+--- 192.168.1.21 ping statistics ---
+3 packets transmitted, 0 packets received, 100.0% packet loss
+`,
+		// 29
+		`PING 10.1.0.1 (10.1.0.1) 1472(1500) bytes of data.
+From 10.0.0.1 icmp_seq=1 Frag needed and DF set (mtu = 1400)
+From 10.0.0.1 icmp_seq=2 Frag needed and DF set (mtu = 1400)
+
+--- 10.1.0.1 ping statistics ---
+2 packets transmitted, 0 received, +2 errors, 100% packet loss, time 1002ms
+`,
+		// 30
+		`PING 192.168.2.10 (192.168.2.10) 56(84) bytes of data.
+From 192.168.1.1: icmp_seq=1 Redirect Host(New nexthop: 192.168.1.254)
+64 bytes from 192.168.2.10: icmp_seq=1 ttl=63 time=0.912 ms
+From 192.168.1.1: icmp_seq=2 Redirect Network(New nexthop: 192.168.1.254)
+64 bytes from 192.168.2.10: icmp_seq=2 ttl=63 time=0.874 ms
+
+--- 192.168.2.10 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1001ms
+rtt min/avg/max/mdev = 0.874/0.893/0.912/0.019 ms
+`,
+		// 31
+		`PING 8.8.8.8 (8.8.8.8) 56(84) bytes of data.
+From 10.1.1.1 icmp_seq=1 Time to live exceeded
+From 10.1.1.1 icmp_seq=2 Time to live exceeded
+
+--- 8.8.8.8 ping statistics ---
+2 packets transmitted, 0 received, +2 errors, 100% packet loss, time 1001ms
+`,
+		// 32
+		crlf(`
+Pinging 8.8.8.8 with 32 bytes of data:
+Reply from 10.1.1.1: TTL expired in transit.
+Reply from 10.1.1.1: TTL expired in transit.
+
+Ping statistics for 8.8.8.8:
+    Packets: Sent = 2, Received = 2, Lost = 0 (0% loss),
+`),
+		// 33
 		`PING 172.16.11.34 (172.16.11.34) 56(84) bytes of data.
 64 bytes from 172.16.11.34: icmp_seq=1 ttl=60 time=216 ms
 78 bytes from 172.16.0.89: icmp_seq=12 ttl=60 time=138 ms
@@ -391,10 +1383,154 @@ round-trip min/avg/max/stddev = 152.070/289.144/449.303/86.309 ms
 --- 172.16.11.34 ping statistics ---
 15 packets transmitted, 19 received, -26% packet loss, time 14015ms
 rtt min/avg/max/mdev = 138.678/181.624/223.131/26.863 ms
-`: "packetLoss: strconv.ParseUint: parsing \"-26\": invalid syntax",
+`,
+		// 34
+		`PING 192.168.1.20 (192.168.1.20): 56 data bytes
+64 bytes from 192.168.1.20: icmp_seq=0 ttl=64 time=3.512 ms
+64 bytes from 192.168.1.20: icmp_seq=1 ttl=64 time=3.877 ms
+64 bytes from 192.168.1.20: icmp_seq=2 ttl=64 time=4.108 ms
+64 bytes from 192.168.1.20: icmp_seq=3 ttl=64 time=3.650 ms
+64 bytes from 192.168.1.20: icmp_seq=5 ttl=64 time=3.799 ms
+
+--- 192.168.1.20 ping statistics ---
+6 packets transmitted, 5 packets received, 16.7% packet loss
+round-trip min/avg/max/stddev = 3.512/3.789/4.108/0.200 ms
+`,
+		// 35
+		`PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.
+64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.052 ms
+64 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=0.061 ms
+64 bytes from 10.0.0.1: icmp_seq=3 ttl=64 time=0.047 ms
+
+--- 10.0.0.1 ping statistics ---
+3 packets transmitted, 3 received, 0% packet loss, time 2ms
+rtt min/avg/max/mdev = 0.047/0.053/0.061/0.005 ms, ipg/ewma 1.024/0.054 ms
+`,
+		// 36
+		`PING 10.0.0.2 (10.0.0.2) 56(84) bytes of data.
+64 bytes from 10.0.0.2: icmp_seq=1 ttl=63 time=20.1 ms
+64 bytes from 10.0.0.2: icmp_seq=2 ttl=63 time=19.8 ms
+
+--- 10.0.0.2 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 10ms
+rtt min/avg/max/mdev = 19.874/19.993/20.112/0.119 ms, pipe 2, ipg/ewma 10.212/20.082 ms
+`,
+		// 37
+		"PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.\n" +
+			"From 10.0.0.254 icmp_seq=1 Destination Host Unreachable\n" +
+			"\r0/1 packets, 100% loss\n" +
+			"64 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=0.030 ms\n" +
+			"64 bytes from 10.0.0.1: icmp_seq=3 ttl=64 time=0.090 ms\n" +
+			"\r2/3 packets, 33% loss, min/avg/ewma/max = 0.030/0.060/0.037/0.090 ms\n" +
+			"\n" +
+			"--- 10.0.0.1 ping statistics ---\n" +
+			"3 packets transmitted, 2 received, +1 errors, 33.3333% packet loss, time 2031ms\n" +
+			"rtt min/avg/max/mdev = 0.030/0.060/0.090/0.030 ms\n",
+		// 38
+		`PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.
+
+--- 10.0.0.1 ping statistics ---
+3 packets transmitted, 3 received, 0% packet loss, time 2003ms
+rtt min/avg/max/mdev = 0.041/0.050/0.061/0.008 ms
+`,
+		// 39
+		`PING 192.168.1.20 (192.168.1.20): 56 data bytes
+--- 192.168.1.20 ping statistics ---
+4 packets transmitted, 3 packets received, 25.0% packet loss
+round-trip min/avg/max/stddev = 3.512/3.810/4.108/0.244 ms
+`,
+		// 40
+		`PING 10.0.0.1 (10.0.0.1): 56 data bytes
+
+--- 10.0.0.1 ping statistics ---
+3 packets transmitted, 3 packets received, 0% packet loss
+round-trip min/avg/max = 0.054/0.061/0.070 ms
+`,
+		// 41
+		"PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.\n" +
+			strings.Repeat(".\b", 5) + "..\n" +
+			"--- 10.0.0.1 ping statistics ---\n" +
+			"7 packets transmitted, 5 received, 28.5714% packet loss, time 12ms\n" +
+			"rtt min/avg/max/mdev = 0.014/0.020/0.031/0.006 ms, ipg/ewma 2.000/0.022 ms\n",
+		// 42
+		"PING 10.0.0.99 (10.0.0.99) 56(84) bytes of data.\n" +
+			".E.E.E.\n" +
+			"--- 10.0.0.99 ping statistics ---\n" +
+			"4 packets transmitted, 0 received, +3 errors, 100% packet loss, time 31ms\n" +
+			"pipe 4\n",
+		// 43
+		`PING 10.0.0.9 (10.0.0.9): 56 data bytes
+92 bytes from 10.0.0.1: Parameter problem: pointer = 0x14
+--- 10.0.0.9 ping statistics ---
+1 packets transmitted, 0 packets received, 100.0% packet loss
+`,
+		// 44
+		`PING 10.0.0.1 (10.0.0.1) 4(32) bytes of data.
+12 bytes from 10.0.0.1: icmp_seq=1 ttl=64
+12 bytes from 10.0.0.1: icmp_seq=2 ttl=64
+
+--- 10.0.0.1 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1ms
+ipg/ewma 0.512/0.000 ms
+`,
+		// 45
+		crlf(`
+Pinging 10.0.0.1 with 1472 bytes of data:
+Reply from 10.0.0.1: bytes=64 (sent 1472) time=1ms TTL=64
+
+Ping statistics for 10.0.0.1:
+    Packets: Sent = 1, Received = 1, Lost = 0 (0% loss),
+Approximate round trip times in milli-seconds:
+    Minimum = 1ms, Maximum = 1ms, Average = 1ms
+`),
+		// 46
+		`PING 10.0.0.1 (10.0.0.1) 4(32) bytes of data.
+12 bytes from 10.0.0.1: icmp_seq=1 ttl=64
+12 bytes from 10.0.0.1: icmp_seq=2 ttl=64
+
+--- 10.0.0.1 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1001ms
+
+`,
+	}
+
+	// output of a ping process killed before printing its statistics
+	interruptedPayload = `PING 172.17.0.7 (172.17.0.7): 64 data bytes
+72 bytes from 172.17.0.7: icmp_seq=0 ttl=61 time=140.850 ms
+72 bytes from 172.17.0.7: icmp_seq=1 ttl=61 time=177.935 ms
+72 bytes from 172.17.0.7: icmp_seq=2 ttl=61 time=138.084 ms
+72 bytes from 172.17.0.7: icmp_seq=3 ttl=61 time=168.642 ms
+72 bytes from 172.17.0.7: icmp_seq=4 ttl=61 time=166.699 ms
+72 bytes from 172.17.0.7: icmp_seq=5 ttl=61 time=167.026 ms
+72 bytes from 172.17.0.7: icmp_seq=6 ttl=61 time=136.036 ms
+72 bytes from 172.17.0.7: icmp_seq=7 ttl=61 time=145.995 ms
+72 bytes from 172.17.0.7: icmp_seq=8 ttl=61 time=145.516 ms
+72 bytes from 172.17.0.7: icmp_seq=9 ttl=61 time=143.324 ms
+72 bytes from 172.17.0.7: icmp_seq=10 ttl=61 time=162.485 ms
+72 bytes from 172.17.0.7: icmp_seq=11 ttl=61 time=161.856 ms
+`
+
+	failedPayloads = map[string]error{
+		interruptedPayload: ErrNotEnoughLines,
+		`ping: unknown host
+`: ErrUnknownHost,
+		crlf(`Ping request could not find host example.invalid. Please check the name and try again.
+`): ErrUnknownHost,
+	}
+	failedPayloadsByErrorString = map[string]string{
+		`PING 172.16.11.34 (172.16.11.34) 56(84) bytes of data.
+
+--- 172.16.11.34 ping statistics ---
+99999999999999999999 packets transmitted, 0 received, 100% packet loss, time 0ms
+`: "line 4 (stats line 1): packetsTransmitted: strconv.ParseUint: parsing \"99999999999999999999\": value out of range",
 	}
 )
 
+// crlf converts the line endings of s to the ones used on Windows.
+func crlf(s string) string {
+	return strings.Replace(s, "\n", "\r\n", -1)
+}
+
 func init() {
 	if len(payloads) != len(expectedTestCases) {
 		panic("invalid payload/testcases defined")
@@ -406,7 +1542,7 @@ func TestPayloadsValidity(t *testing.T) {
 		if tc.Stats.PacketsTransmitted == 0 {
 			t.Errorf("testcase #%d: no packets transmitted", i)
 		}
-		if tc.Stats.PacketLossPercent == 0 && (tc.Stats.Warning == `` && tc.Stats.PacketsReceived != tc.Stats.PacketsTransmitted) {
+		if tc.Stats.PacketLossPercent == 0 && !tc.Stats.NegativePacketLoss && (tc.Stats.Warning == `` && tc.Stats.PacketsReceived != tc.Stats.PacketsTransmitted) {
 			t.Errorf("testcase #%d: invalid packet loss percentage", i)
 		}
 	}
@@ -425,6 +1561,10 @@ func TestPings(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			if po.Dialect != expected.Dialect {
+				t.Errorf("expected dialect %q, but got %q", expected.Dialect, po.Dialect)
+			}
+
 			if po.Host != expected.Host {
 				t.Errorf("expected host %q, but got %q", expected.Host, po.Host)
 			}
@@ -454,6 +1594,9 @@ func TestPings(t *testing.T) {
 			if po.Stats.PacketLossPercent != expected.Stats.PacketLossPercent {
 				t.Errorf("expected packet loss percent %v, but got %v", expected.Stats.PacketLossPercent, po.Stats.PacketLossPercent)
 			}
+			if po.Stats.PacketLoss != expected.Stats.PacketLoss || po.Stats.NegativePacketLoss != expected.Stats.NegativePacketLoss {
+				t.Errorf("expected packet loss %v (negative %v), but got %v (negative %v)", expected.Stats.PacketLoss, expected.Stats.NegativePacketLoss, po.Stats.PacketLoss, po.Stats.NegativePacketLoss)
+			}
 			if po.Stats.Time != expected.Stats.Time {
 				t.Errorf("expected time %v, but got %v", expected.Stats.Time, po.Stats.Time)
 			}
@@ -477,10 +1620,26 @@ func TestPings(t *testing.T) {
 			if po.Stats.PacketsTransmitted != expected.Stats.PacketsTransmitted {
 				t.Errorf("expected packets transmitted %v, but got %v", expected.Stats.PacketsTransmitted, po.Stats.PacketsTransmitted)
 			}
+			if po.Stats.InterPacketGap != expected.Stats.InterPacketGap || po.Stats.RoundTripEWMA != expected.Stats.RoundTripEWMA {
+				t.Errorf("expected ipg/ewma %v/%v, but got %v/%v", expected.Stats.InterPacketGap, expected.Stats.RoundTripEWMA, po.Stats.InterPacketGap, po.Stats.RoundTripEWMA)
+			}
+			if po.Stats.Duplicates != expected.Stats.Duplicates {
+				t.Errorf("expected duplicates %v, but got %v", expected.Stats.Duplicates, po.Stats.Duplicates)
+			}
+			if po.Stats.Pipe != expected.Stats.Pipe {
+				t.Errorf("expected pipe %v, but got %v", expected.Stats.Pipe, po.Stats.Pipe)
+			}
+			if err := po.CheckDuplicates(); err != nil {
+				t.Error(err)
+			}
 			if po.Stats.Warning != expected.Stats.Warning {
 				t.Errorf("expected stats warning %q, but got %q", expected.Stats.Warning, po.Stats.Warning)
 			}
 
+			if !reflect.DeepEqual(po.Snapshots, expected.Snapshots) {
+				t.Errorf("expected snapshots %#v, but got %#v", expected.Snapshots, po.Snapshots)
+			}
+
 			if len(expected.Replies) != len(po.Replies) {
 				t.Errorf("expected %d replies, but got %d %#v", len(expected.Replies), len(po.Replies), po.Replies)
 			}
@@ -506,6 +1665,9 @@ func TestPings(t *testing.T) {
 				if epr.FromAddress != pr.FromAddress {
 					t.Errorf("reply %d: expected from address %v, but got %v", i, epr.FromAddress, pr.FromAddress)
 				}
+				if epr.FromHost != pr.FromHost {
+					t.Errorf("reply %d: expected from host %q, but got %q", i, epr.FromHost, pr.FromHost)
+				}
 				if epr.SequenceNumber != pr.SequenceNumber {
 					t.Errorf("reply %d: expected sequence number %v, but got %v", i, epr.SequenceNumber, pr.SequenceNumber)
 				}
@@ -515,9 +1677,27 @@ func TestPings(t *testing.T) {
 				if epr.Error != pr.Error {
 					t.Errorf("reply %d: expected error %q, but got %q", i, epr.Error, pr.Error)
 				}
+				if epr.ErrorKind != pr.ErrorKind || epr.ICMPType != pr.ICMPType || epr.ICMPCode != pr.ICMPCode {
+					t.Errorf("reply %d: expected error kind %v (type %d, code %d), but got %v (type %d, code %d)", i, epr.ErrorKind, epr.ICMPType, epr.ICMPCode, pr.ErrorKind, pr.ICMPType, pr.ICMPCode)
+				}
+				if epr.MTU != pr.MTU {
+					t.Errorf("reply %d: expected MTU %d, but got %d", i, epr.MTU, pr.MTU)
+				}
+				if epr.Redirect != pr.Redirect || epr.NextHop != pr.NextHop {
+					t.Errorf("reply %d: expected redirect %v to %q, but got %v to %q", i, epr.Redirect, epr.NextHop, pr.Redirect, pr.NextHop)
+				}
+				if epr.TTLExceeded != pr.TTLExceeded {
+					t.Errorf("reply %d: expected TTL exceeded %v, but got %v", i, epr.TTLExceeded, pr.TTLExceeded)
+				}
 				if epr.Duplicate != pr.Duplicate {
 					t.Errorf("reply %d: expected duplicate %v, but got %v", i, epr.Duplicate, pr.Duplicate)
 				}
+				if epr.Timeout != pr.Timeout {
+					t.Errorf("reply %d: expected timeout %v, but got %v", i, epr.Timeout, pr.Timeout)
+				}
+				if !epr.Timestamp.Equal(pr.Timestamp) {
+					t.Errorf("reply %d: expected timestamp %v, but got %v", i, epr.Timestamp, pr.Timestamp)
+				}
 			}
 
 		})
@@ -529,7 +1709,7 @@ func TestFailed(t *testing.T) {
 	for payload, expectedError := range failedPayloads {
 		i++
 		_, err := Parse(payload)
-		if err != expectedError {
+		if !errors.Is(err, expectedError) {
 			t.Errorf("failed payload #%d: expected %v but got %v", i, expectedError, err)
 		}
 
@@ -547,3 +1727,144 @@ func TestFailedByErrorString(t *testing.T) {
 
 	}
 }
+
+func TestStatsSeparatorWithoutReplies(t *testing.T) {
+	// the separator is parsed even when a blank line follows the header directly
+	po, err := Parse(`PING example.com (93.184.216.34) 56(84) bytes of data.
+
+--- example.com ping statistics ---
+2 packets transmitted, 0 received, 100% packet loss, time 1021ms
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.Stats.IPAddress != `example.com` {
+		t.Errorf("expected statistics for %q, but got %q", `example.com`, po.Stats.IPAddress)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	po, err := Parse(interruptedPayload, AllowPartial())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(po.Replies) != 12 {
+		t.Errorf("expected 12 replies, but got %d", len(po.Replies))
+	}
+	expected := PingStatistics{
+		IPAddress:          `172.17.0.7`,
+		PacketsTransmitted: 12,
+		PacketsReceived:    12,
+		RoundTripMin:       136036 * time.Microsecond,
+		RoundTripMax:       177935 * time.Microsecond,
+		RoundTripAverage:   154537333,
+		RoundTripDeviation: 13685281,
+		Synthesized:        true,
+	}
+	if po.Stats != expected {
+		t.Errorf("expected statistics %#v, but got %#v", expected, po.Stats)
+	}
+
+	// stats line 2 is missing, the packet counters are kept
+	truncated := payloads[0][:strings.Index(payloads[0], "rtt ")]
+	if _, err := Parse(truncated); !errors.Is(err, ErrNotEnoughLines) {
+		t.Errorf("expected %v, but got %v", ErrNotEnoughLines, err)
+	}
+	po, err = Parse(truncated, AllowPartial())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = expectedTestCases[0].Stats
+	expected.RoundTripDeviation = 4082
+	expected.Synthesized = true
+	if po.Stats != expected {
+		t.Errorf("expected statistics %#v, but got %#v", expected, po.Stats)
+	}
+	// a late reply answers the request reported without answer by ping -O
+	po, err = Parse(`PING 10.0.0.1 (10.0.0.1) 56(84) bytes of data.
+64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.045 ms
+no answer yet for icmp_seq=2
+64 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=1012 ms
+64 bytes from 10.0.0.1: icmp_seq=3 ttl=64 time=0.051 ms
+`, AllowPartial())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.Stats.PacketsTransmitted != 3 || po.Stats.PacketsReceived != 3 || po.Stats.PacketLoss != 0 {
+		t.Errorf("expected 3 packets transmitted and received, but got %d/%d (%v%% loss)", po.Stats.PacketsTransmitted, po.Stats.PacketsReceived, po.Stats.PacketLoss)
+	}
+
+	// without sequence numbers, each line is a request
+	po, err = Parse(crlf(`
+Pinging 10.0.0.1 with 32 bytes of data:
+Request timed out.
+Reply from 10.0.0.1: bytes=32 time=1ms TTL=64
+`), AllowPartial())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.Stats.PacketsTransmitted != 2 || po.Stats.PacketsReceived != 1 {
+		t.Errorf("expected 2 packets transmitted and 1 received, but got %d/%d", po.Stats.PacketsTransmitted, po.Stats.PacketsReceived)
+	}
+}
+
+func TestLenient(t *testing.T) {
+	payload := `PING 127.0.0.1 (127.0.0.1) 56(84) bytes of data.
+64 bytes from 127.0.0.1: icmp_seq=1 ttl=64 time=0.026 ms
+ping: sendmsg: No buffer space available
+64 bytes from 127.0.0.1: icmp_seq=3 ttl=64 time=0.031 ms
+
+--- 127.0.0.1 ping statistics ---
+3 packets transmitted, 2 received, 33% packet loss, time 2002ms
+rtt min/avg/max/mdev = 0.026/0.028/0.031/0.002 ms
+`
+	if _, err := Parse(payload); !errors.Is(err, ErrUnrecognizedLine) {
+		t.Errorf("expected %v, but got %v", ErrUnrecognizedLine, err)
+	}
+
+	po, err := Parse(payload, Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(po.Replies) != 2 {
+		t.Errorf("expected 2 replies, but got %d", len(po.Replies))
+	}
+	if po.Stats.RoundTripMax != 31*time.Microsecond {
+		t.Errorf("expected rtt max %v, but got %v", 31*time.Microsecond, po.Stats.RoundTripMax)
+	}
+	expected := []UnrecognizedLine{{Number: 3, Text: "ping: sendmsg: No buffer space available"}}
+	if !reflect.DeepEqual(po.UnrecognizedLines, expected) {
+		t.Errorf("expected unrecognized lines %#v, but got %#v", expected, po.UnrecognizedLines)
+	}
+
+	// conversion errors are not skipped
+	if _, err := Parse(strings.Replace(payload, "time=0.031 ms", "time=0.031 parsecs", 1), Lenient()); !errors.As(err, new(ConversionError)) {
+		t.Errorf("expected a conversion error, but got %v", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse(payloads[0][:strings.Index(payloads[0], "rtt ")] + "rtt min/avg/max/mdev = 0.021/0.026/0.031/0.004 parsecs\n")
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a *ParseError, but got %v", err)
+	}
+	if pe.Line != 8 {
+		t.Errorf("expected line 8, but got %d", pe.Line)
+	}
+	if pe.Text != "rtt min/avg/max/mdev = 0.021/0.026/0.031/0.004 parsecs" {
+		t.Errorf("unexpected line text %q", pe.Text)
+	}
+	if pe.State != StateStatsLine2 {
+		t.Errorf("expected state %v, but got %v", StateStatsLine2, pe.State)
+	}
+
+	var ce ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected a ConversionError, but got %v", err)
+	}
+	if ce.Context != "rtt" {
+		t.Errorf("expected context %q, but got %q", "rtt", ce.Context)
+	}
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// countReplies computes the packet counters of stats from the replies. Since lost requests
+// leave no trace in the output, a request is assumed to have been sent for each sequence
+// number between the lowest and the highest one seen. Without sequence numbers, as printed
+// by Windows, each line is counted as a request of its own.
+func countReplies(replies []PingReply, stats *PingStatistics) {
+	var (
+		minSeqNo, maxSeqNo uint
+		seen               uint
+		sequenced          bool
+	)
+	answered := make(map[uint]bool)
+	for _, pr := range replies {
+		if pr.SequenceNumber != 0 {
+			sequenced = true
+		}
+		if !pr.Duplicate && !pr.Timeout {
+			answered[pr.SequenceNumber] = true
+		}
+	}
+
+	stats.PacketsReceived = 0
+	stats.Errors = 0
+	stats.Duplicates = countDuplicates(replies)
+	seqNos := make(map[uint]bool)
+	for _, pr := range replies {
+		if pr.Duplicate {
+			continue
+		}
+		// a late reply supersedes the timeout reported for its request
+		if sequenced && pr.Timeout && answered[pr.SequenceNumber] {
+			continue
+		}
+		switch {
+		case pr.Timeout:
+			// only counts as transmitted
+		case pr.Error != ``:
+			stats.Errors++
+		default:
+			stats.PacketsReceived++
+		}
+		if seen == 0 || pr.SequenceNumber < minSeqNo {
+			minSeqNo = pr.SequenceNumber
+		}
+		if seen == 0 || pr.SequenceNumber > maxSeqNo {
+			maxSeqNo = pr.SequenceNumber
+		}
+		seqNos[pr.SequenceNumber] = true
+		seen++
+	}
+
+	stats.PacketsTransmitted = seen
+	if sequenced {
+		stats.PacketsTransmitted = uint(len(seqNos))
+	}
+	if seen != 0 && maxSeqNo-minSeqNo+1 > stats.PacketsTransmitted {
+		stats.PacketsTransmitted = maxSeqNo - minSeqNo + 1
+	}
+	stats.PacketLossPercent, stats.PacketLoss, stats.NegativePacketLoss = 0, 0, false
+	if stats.PacketsTransmitted != 0 {
+		stats.PacketLossPercent = uint8((stats.PacketsTransmitted - stats.PacketsReceived) * 100 / stats.PacketsTransmitted)
+		stats.PacketLoss = float64(stats.PacketsTransmitted-stats.PacketsReceived) * 100 / float64(stats.PacketsTransmitted)
+	}
+}
+
+// computeRoundTrip computes the round-trip summary of stats from the valid replies, with the
+// deviation calculated the same way as iputils' mdev.
+func computeRoundTrip(replies []PingReply, stats *PingStatistics) {
+	var (
+		sum, sum2 float64
+		n         int
+	)
+	stats.RoundTripMin, stats.RoundTripMax = 0, 0
+	for _, pr := range replies {
+		if pr.Error != `` || pr.Duplicate || pr.Timeout {
+			continue
+		}
+		if n == 0 || pr.Time < stats.RoundTripMin {
+			stats.RoundTripMin = pr.Time
+		}
+		if n == 0 || pr.Time > stats.RoundTripMax {
+			stats.RoundTripMax = pr.Time
+		}
+		sum += float64(pr.Time)
+		sum2 += float64(pr.Time) * float64(pr.Time)
+		n++
+	}
+	if n == 0 {
+		stats.RoundTripAverage, stats.RoundTripDeviation = 0, 0
+		return
+	}
+
+	avg := sum / float64(n)
+	stats.RoundTripAverage = time.Duration(avg)
+	stats.RoundTripDeviation = time.Duration(math.Sqrt(math.Max(sum2/float64(n)-avg*avg, 0)))
+}
+
+// countDuplicates returns the number of replies flagged as duplicates.
+func countDuplicates(replies []PingReply) uint {
+	var n uint
+	for _, pr := range replies {
+		if pr.Duplicate {
+			n++
+		}
+	}
+
+	return n
+}
+
+// CheckDuplicates reports whether the replies flagged as duplicates match the number of
+// duplicates in the statistics, as ping counts them separately; it returns an error wrapping
+// ErrDuplicatesMismatch otherwise. Outputs without replies, such as quiet ones, are not checked.
+func (po *PingOutput) CheckDuplicates() error {
+	if len(po.Replies) == 0 {
+		return nil
+	}
+	if n := countDuplicates(po.Replies); n != po.Stats.Duplicates {
+		return fmt.Errorf("%w: %d flagged, %d reported", ErrDuplicatesMismatch, n, po.Stats.Duplicates)
+	}
+
+	return nil
+}